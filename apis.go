@@ -2,6 +2,7 @@ package apis
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/cockroachdb/apd/v3"
@@ -16,11 +17,9 @@ const (
 	both
 )
 
-type item struct {
-	b bound
-	d apd.Decimal
-	open bool
-}
+// item is a bound of a Set. Set uses the same endpoints as the generic ranges,
+// so that they share one sweep.
+type item = endpoint[apd.Decimal]
 
 type Set struct {
 	items []item
@@ -185,7 +184,7 @@ func New(l apd.Decimal, lOpen bool, u apd.Decimal, uOpen bool) Set {
 			}
 		}
 	}
-	return s
+	return s.own()
 }
 
 func NewFromStrings(ls string, us string) (Set, error) {
@@ -220,529 +219,127 @@ func init() {
 }
 
 func (a Set) Complement() Set {
-	newItems := []item{}
-
-
-	if len(a.items) == 1 {
-		// Special case. The 
-		newItems = append(newItems, item{
-			d: negativeInfinity,
-			b: lower,
-			open: true, // By definiton
-		})
-		newItems = append(newItems, item{
-			d: a.items[0].d,
-			b: both,
-			open: false, // By definition
-		})
-		newItems = append(newItems, item{
-			d: positiveInfinity,
-			b: upper,
-			open: true, // By definiton
-		})
-	} else {
-		for i, v := range a.items {
-			if v.b == both {
-				// No need to change open/closed, as singular values are by definition closed.
-				if i == 0 {
-					newItems = append(newItems, item{
-						d: negativeInfinity,
-						b: lower,
-						open:true, // By definition
-					})
-					newItems = append(newItems, v)
-				} else if i == len(a.items) - 1 {
-					newItems = append(newItems, v)
-					newItems = append(newItems, item{
-						d: positiveInfinity,
-						b: upper,
-						open:true, // By definition
-					})
-				} else {
-					newItems = append(newItems, v)
-				}
-				continue
-			}
-			if v.b == lower {
-				if i == 0 {
-					if isNegativeInfinity(v.d) {
-						//Don't need to include this
-					} else {
-						newItems = append(newItems, item{
-							d: negativeInfinity,
-							b: lower,
-							open: true, // by definition
-						})
-						newItems = append(newItems, item{
-							d: v.d,
-							b: upper,
-							open: !v.open,
-						})
-					}
-				} else {
-					newItems = append(newItems, item{
-						d:v.d,
-						b:upper,
-						open: !v.open,
-					})
-				}
-			} else {
-				if i == len(a.items) - 1 {
-					if isPositiveInfinity(v.d) {
-						// Skip
-					} else {
-						newItems = append(newItems, item{
-							d: v.d,
-							b: lower,
-							open: !v.open,
-						})
-						newItems = append(newItems, item{
-							d: positiveInfinity,
-							b: upper,
-							open: true,
-						})
-
-					}
-				} else {
-					newItems = append(newItems, item{
-						d:v.d,
-						b:lower,
-						open: !v.open,
-					})
-				}
-			}
-		}
-	}
-
-	return Set{
-		items: newItems,
-	}
+	res := Set{items: make([]item, len(a.items), len(a.items)+2)}
+	copy(res.items, a.items)
+	res.ComplementInPlace()
+	return res.own()
 }
 
-func (a Set)Intersection(b Set) Set {
-	newItems := []item{}
-	ai := 0
-	bi := 0
-
-	aCurrentInSet := false
-	bCurrentInSet := false
-
+// cloneDecimal returns a copy of d that shares no memory with it. Copying an
+// apd.Decimal by value shares its coefficient's digits once they outgrow the
+// space within the Decimal itself.
+func cloneDecimal(d apd.Decimal) apd.Decimal {
 	var c apd.Decimal
+	c.Set(&d)
+	return c
+}
 
-	for {
-		if ai >= len(a.items)  || bi >= len(b.items){
-			// No more intersections possible.
-			break
-		}
-
-		av := a.items[ai]
-		bv := b.items[bi]
-
-		// TODO: errors
-		_, _ = apd.BaseContext.Cmp(&c, &av.d, &bv.d)
-
-		if c.IsZero() {
-			if av.b == both {
-				if aCurrentInSet {
-					// Exclusion in A
-					if bv.b == both {
-						// Exlcuded in both
-						if bCurrentInSet {
-							newItems = append(newItems, av)
-						} else {
-							// Inclusion in B, exclusion in A, skip as no set is open.
-						}
-					} else if bv.b == lower {
-						// Not currently in any set, boundary is excluded, but A is not in-set above the point
-						newItems = append(newItems, item {
-							lower,
-							bv.d,
-							true,
-						})
-					} else if bv.b == upper {
-						// Interval is terminating, boundary value explictly excluded from A, so boundary is open.
-						newItems = append(newItems, item {
-							upper,
-							bv.d,
-							true,
-						})
-					}
-				} else {
-					// Inclusion in A
-					if bv.b == both {
-						if bCurrentInSet {
-							// Exclusion in B, no set is open, no need to exclude.
-						} else {
-							// Both explicitly included.
-							newItems = append(newItems, av)
-						}
-					} else if bv.b == lower {
-						newItems = append(newItems, item{
-							both,
-							av.d,
-							false,
-						})
-					} else if bv.b == upper {
-						// Intersection not in current interval as A not in current internal
-					}
-				}
-			} else if av.b == lower {
-				if bv.b == lower {
-					// Both opening, interval is closed if both are closed
-					newItems = append(newItems, item{
-						lower,
-						av.d,
-						av.open || bv.open,
-					})
-				} else if bv.b == upper {
-					// A terminating, b starting
-					// If neither is open
-					if !(av.open || bv.open) {
-						newItems = append(newItems, item{
-							both,
-							av.d,
-							false,	// by defintiion
-						})
-					}
-				} else if bv.b == both {
-					if bCurrentInSet {
-						// Exclusion in B, A is beginning, 
-						newItems = append(newItems, item {
-							lower,
-							av.d,
-							true,
-						})
-					} else {
-						// Inclusion in B, open interval in A
-						if !av.open {
-							newItems = append(newItems, item {
-								both,
-								av.d,
-								false,
-							})
-						}
-					}
-				}
-			} else if av.b == upper{
-				// terminating A
-				if bv.b == lower {
-					if !(av.open || bv.open) {
-						newItems = append(newItems, item {
-							both,
-							av.d,
-							false,
-						})
-					}
-				} else if bv.b == upper {
-					newItems = append(newItems, item{
-						upper,
-						av.d,
-						av.open || bv.open,
-					})
-				} else if bv.b == both {
-					if bCurrentInSet {
-						newItems = append(newItems, item{
-							upper,
-							av.d,
-							true,
-						})
-					} else {
-						if !av.open {
-							newItems = append(newItems, item{
-								both,
-								av.d,
-								false,
-							})
-						}
-					}
-				}
-			}
-
-
-			if av.b == lower {
-				aCurrentInSet = true
-			} else if av.b == upper {
-				aCurrentInSet = false
-			}
-
-			if bv.b == lower {
-				bCurrentInSet = true
-			} else if bv.b == upper {
-				bCurrentInSet = false
-			}
+// own replaces the decimals of newly built items with copies, so that the set
+// shares no memory with the sets or decimals it was built from.
+func (s Set) own() Set {
+	for i := range s.items {
+		s.items[i].d = cloneDecimal(s.items[i].d)
+	}
+	return s
+}
 
-			ai++
-			bi++
-			continue
-		}
+// Clone returns a copy of s that shares no memory with it. Every operation
+// already returns sets that share no memory with their inputs, so this is
+// only needed before updating a set in place, as UnionWith does.
+func (s Set) Clone() Set {
+	return Set{items: append([]item(nil), s.items...)}.own()
+}
 
-		if c.Negative {
-			// fmt.Println("In negative")
-			// process a
-			if bCurrentInSet {
-				// If a is termianting, terminate both,
-				// If a is beginning, begin both,
-				// If a is an exlcusion or inclusion, it applies.
-				newItems = append(newItems, av)
-			} else {
-				// Need both to be active
-			}
-			switch av.b {
-			case both:
-				// Nothing
-			case lower:
-				aCurrentInSet = true
-			case upper:
-				aCurrentInSet = false
-			}
-			ai++
-		} else {
-			// fmt.Println("In positive")
-			// process b
-			if aCurrentInSet {
-				newItems = append(newItems, bv)
-			} else {
-			
-			}
-			switch bv.b {
-			case both:
-				// Nothing
-			case lower:
-				bCurrentInSet = true
-			case upper:
-				bCurrentInSet = false
-			}
-			bi++
-		}
+// compareDecimals orders decimals for the generic ranges functions.
+func compareDecimals(a, b apd.Decimal) int {
+	return a.Cmp(&b)
+}
 
-	}
+// combine evaluates op on the membership of a and b at and between every
+// item, in a single sweep. op(false, false) must be false, as nothing lies
+// below the first item or above the last.
+func (a Set) combine(b Set, op func(inA, inB bool) bool) Set {
+	return combineSets(nil, a, b, op)
+}
 
-	return Set{newItems}
+// combineSets is combine, appending the result's items to dst[:0].
+func combineSets(dst []item, a, b Set, op func(inA, inB bool) bool) Set {
+	r := combineInto(dst, ranges[apd.Decimal]{items: a.items}, ranges[apd.Decimal]{items: b.items}, compareDecimals, op)
+	return Set{items: r.items}.own()
 }
 
+func opUnion(inA, inB bool) bool               { return inA || inB }
+func opIntersection(inA, inB bool) bool        { return inA && inB }
+func opDifference(inA, inB bool) bool          { return inA && !inB }
+func opSymmetricDifference(inA, inB bool) bool { return inA != inB }
 
-func (a Set)Union(b Set) Set {
+func (a Set) Intersection(b Set) Set {
+	return a.combine(b, opIntersection)
+}
 
-	newItems := []item{}
+func (a Set) Union(b Set) Set {
+	return a.combine(b, opUnion)
+}
 
-	ai := 0
-	bi := 0
+// Difference returns the numbers in a but not in b.
+func (a Set) Difference(b Set) Set {
+	return a.combine(b, opDifference)
+}
 
-	aCurrentInSet := false
-	bCurrentInSet := false
+// SymmetricDifference returns the numbers in exactly one of a and b.
+func (a Set) SymmetricDifference(b Set) Set {
+	return a.combine(b, opSymmetricDifference)
+}
 
-	var c apd.Decimal
+func (s Set) IsEmpty() bool {
+	return len(s.items) == 0
+}
 
-	for {
-		// rset := Set{newItems}
-		// fmt.Printf("Set: '%v', %v, %v, %v, %v \n", rset.String(), ai, bi, aCurrentInSet, bCurrentInSet)
-		if ai >= len(a.items) {
-			// Done with a, flush b into new items
-			// fmt.Println("flushing b")
-			for ; bi < len(b.items); bi++ {
-				newItems = append(newItems, b.items[bi])
-			}
-			// rset := Set{newItems}
-			// fmt.Printf("Final Set: '%v', %v, %v\n", rset.String(), ai, bi)
+func (s Set) Contains(d apd.Decimal) bool {
+	// First item at or above d.
+	i := sort.Search(len(s.items), func(i int) bool {
+		return s.items[i].d.Cmp(&d) >= 0
+	})
+
+	// Membership below item i is determined by the nearest lower or upper
+	// bound, as 'both' items do not change it.
+	in := false
+	for j := i; j < len(s.items); j++ {
+		if s.items[j].b != both {
+			in = s.items[j].b == upper
 			break
 		}
-		if bi >= len(b.items) {
-			// Done with b, flush a into new items
-			for ; ai < len(a.items); ai++ {
-				newItems = append(newItems, a.items[ai])
-			}
-			break
+	}
+	if i < len(s.items) && s.items[i].d.Cmp(&d) == 0 {
+		switch s.items[i].b {
+		case both:
+			return !in
+		default:
+			return !s.items[i].open
 		}
+	}
+	return in
+}
 
-
-		av := a.items[ai]
-		bv := b.items[bi]
-
-		// TODO: errors
-		_, _ = apd.BaseContext.Cmp(&c, &av.d, &bv.d)
-
-		if c.IsZero() {
-			// fmt.Println("In equal")
-			if av.b == both {
-				if aCurrentInSet {
-					// Exlcusion in A
-					if bv.b == both {
-						if bCurrentInSet {
-							// Exlusion in B -- they are identical. 
-							newItems = append(newItems, av)
-						} else {
-							// Excluded in A, but explicitly included in B
-							// skip
-						}
-					} else if bv.b == lower {
-						if bv.open {
-							// The exlcusion is not included in the opening B interval
-							newItems = append(newItems, av)
-						} else {
-							// B includes the exlcusion in A, keep the interval open./
-						}
-					} else {
-						// b is leaving set, a will remain open 
-						if bv.open {
-							// b does not contain the value
-							newItems = append(newItems, av)
-						} else {
-							// Skip
-						}
-					}
-				} else {
-					// Inclusion in A
-					if bv.b == both {
-						if bCurrentInSet {
-							// Exlusion in B, inclusion in A. Cancel each other out -- skip
-						} else {
-							// Both explictly included
-							newItems = append(newItems, av)
-						}
-					} else {
-						// REgardless of whether b was open or closed, this boundary is now closed because 
-						// A explicitly inclues that value
-						newItems = append(newItems, item{
-							bv.b,
-							bv.d,
-							false,	// The boundary point is included. 
-						})
-					}
-				}
-			} else if av.b == lower {
-				// fmt.Println("avb lower")
-
-				if bv.b == lower  {
-					// Both lower bounds, set is closed if either are closed
-					newItems = append(newItems, item{
-						bv.b,
-						bv.d,
-						bv.open && av.open,
-					})
-				} else if bv.b == upper {
-					// If  both are open, need a new inclusion
-					if bv.open && av.open {
-						newItems = append(newItems, item{
-							both,
-							bv.d,
-							false,
-						})
-					} else {
-						// skip both, 
-					}
-				} else {
-					if bCurrentInSet {
-						// Exclusion in b, end of A
-						if !av.open {
-							// Skip both
-						} else {
-							newItems = append(newItems, bv)
-						}
-					} else {
-						// Inclusion in B, set opening A
-						// By definition closed
-						newItems = append(newItems, item{
-							av.b,
-							av.d,
-							false,
-						})
-					}
-				}
-			} else {
-				// fmt.Println("avb upper")
-				// A ending
-				if bv.b == upper  {
-					newItems = append(newItems, item{
-						bv.b,
-						bv.d,
-						bv.open && av.open,
-					})
-				} else if bv.b == lower {
-					// B begining
-					if av.open && bv.open {
-						newItems = append(newItems, item{
-							both,
-							bv.d,
-							false,
-						})
-					} else {
-						// AT least one is closed, skip both
-					}
-				} else {
-					if bCurrentInSet {
-						if av.open {
-							newItems = append(newItems, bv)
-						} else {
-							// Skip -- point is included in A, so interval continues
-						}
-						// Exclusion in b, inclusion in A (if closed)
-						// Skip both
-					} else {
-						// Inclusion in B, set closing A
-						// result is always closed
-						newItems = append(newItems, item{
-							av.b,
-							av.d,
-							false,
-						})
-					}
-				}
-			}
-
-			if av.b == lower {
-				aCurrentInSet = true
-			} else if av.b == upper {
-				aCurrentInSet = false
-			}
-
-			if bv.b == lower {
-				bCurrentInSet = true
-			} else if bv.b == upper {
-				bCurrentInSet = false
-			}
-
-			ai++
-			bi++
-			continue
-		}
-		if c.Negative {
-			// fmt.Println("In negative")
-			// process a
-			if bCurrentInSet {
-					
-			} else {
-				newItems = append(newItems, av)
-			}
-			switch av.b {
-			case both:
-				// Nothing
-			case lower:
-				aCurrentInSet = true
-			case upper:
-				aCurrentInSet = false
-			}
-			ai++
-		} else {
-			// fmt.Println("In positive")
-			// process b
-			if aCurrentInSet {
-			} else {
-				newItems = append(newItems, bv)
-			}
-			switch bv.b {
-			case both:
-				// Nothing
-			case lower:
-				bCurrentInSet = true
-			case upper:
-				bCurrentInSet = false
-			}
-			bi++
+// Equal reports whether a and b contain the same numbers. As every operation
+// produces a canonical form, this compares items, ignoring trailing zeros.
+func (a Set) Equal(b Set) bool {
+	if len(a.items) != len(b.items) {
+		return false
+	}
+	for i := range a.items {
+		av, bv := a.items[i], b.items[i]
+		if av.b != bv.b || av.open != bv.open || av.d.Cmp(&bv.d) != 0 {
+			return false
 		}
-		
 	}
+	return true
+}
 
-	return Set{
-		items: newItems,
-	}
-}
\ No newline at end of file
+// IsSubsetOf reports whether every number in a is also in b. Like the other
+// binary operations, it gallops over the larger set, so a small set is
+// checked against a large one in time logarithmic in the large one's size.
+// It stops at the first number in a but not in b, and does not allocate.
+func (a Set) IsSubsetOf(b Set) bool {
+	return ranges[apd.Decimal]{items: a.items}.isSubsetOf(ranges[apd.Decimal]{items: b.items}, compareDecimals)
+}
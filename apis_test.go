@@ -102,6 +102,8 @@ func TestIntersect(t *testing.T) {
 
 	cases := []testcase{
 		{[]boundDef{{"0", false}, { "0", false}, {"0", false}, {"infinity", true}}, "[0, 0]"},
+		{[]boundDef{{"3", false}, {"3", false}, {"-infinity", true}, {"3", false}}, "[3, 3]"},
+		{[]boundDef{{"3", false}, {"3", false}, {"3", true}, {"5", false}}, ""},
 	}
 
 	for _, c := range cases {
@@ -235,3 +237,125 @@ func FuzzOperations(f *testing.F) {
 		}
 	})
 }
+
+func TestEqual(t *testing.T) {
+	a := newFromBounds(boundDef{"1", false}, boundDef{"2", true})
+	b := newFromBounds(boundDef{"1.0", false}, boundDef{"2.00", true})
+	c := newFromBounds(boundDef{"1", false}, boundDef{"2", false})
+
+	if !a.Equal(b) {
+		t.Fatalf("Expected '%v' to equal '%v'", a.String(), b.String())
+	}
+	if a.Equal(c) {
+		t.Fatalf("Expected '%v' not to equal '%v'", a.String(), c.String())
+	}
+	if !a.Complement().Complement().Equal(a) {
+		t.Fatalf("Expected double complement of '%v' to equal itself", a.String())
+	}
+}
+
+func TestDifference(t *testing.T) {
+	type testcase struct {
+		b          []boundDef
+		difference string
+		symmetric  string
+	}
+
+	cases := []testcase{
+		{[]boundDef{{"0", false}, {"10", false}, {"5", false}, {"15", false}}, "[0, 5)", "[0, 5), (10, 15]"},
+		{[]boundDef{{"0", false}, {"10", false}, {"5", true}, {"10", true}}, "[0, 5], [10, 10]", "[0, 5], [10, 10]"},
+		{[]boundDef{{"0", false}, {"10", false}, {"5", false}, {"5", false}}, "[0, 5), (5, 10]", "[0, 5), (5, 10]"},
+		{[]boundDef{{"0", false}, {"10", true}, {"10", false}, {"20", false}}, "[0, 10)", "[0, 20]"},
+		{[]boundDef{{"0", false}, {"10", false}, {"-infinity", true}, {"infinity", true}}, "", "(-Infinity, 0), (10, Infinity)"},
+		{[]boundDef{{"0", false}, {"10", false}, {"0", false}, {"10", false}}, "", ""},
+	}
+
+	for _, c := range cases {
+		t.Run(c.difference, func(t *testing.T) {
+			l := newFromBounds(c.b[0], c.b[1])
+			m := newFromBounds(c.b[2], c.b[3])
+
+			d := l.Difference(m)
+			if err := d.Validate(); err != nil {
+				t.Fatal(err)
+			}
+			if r := d.String(); r != c.difference {
+				t.Fatalf("Expected '%v', but got '%v'", c.difference, r)
+			}
+
+			s := l.SymmetricDifference(m)
+			if err := s.Validate(); err != nil {
+				t.Fatal(err)
+			}
+			if r := s.String(); r != c.symmetric {
+				t.Fatalf("Expected '%v', but got '%v'", c.symmetric, r)
+			}
+		})
+	}
+}
+
+// oracleContains decides membership of x in the set that fuzzSet builds from
+// the same five bytes, directly from the definition of New.
+func oracleContains(b []byte, x apd.Decimal) bool {
+	l, lOpen, u, uOpen := getValue(b[0]), getOpen(b[1]), getValue(b[2]), getOpen(b[3])
+	if l.Cmp(&u) > 0 {
+		l, lOpen, u, uOpen = u, uOpen, l, lOpen
+	}
+	in := false
+	switch c := l.Cmp(&u); {
+	case c == 0:
+		in = l.Form != apd.Infinite && x.Cmp(&l) == 0
+	default:
+		lc, uc := l.Cmp(&x), x.Cmp(&u)
+		in = (lc < 0 || lc == 0 && !lOpen && l.Form != apd.Infinite) && (uc < 0 || uc == 0 && !uOpen && u.Form != apd.Infinite)
+	}
+	return in != (b[4]%2 == 1)
+}
+
+func fuzzSet(b []byte) Set {
+	s := New(getValue(b[0]), getOpen(b[1]), getValue(b[2]), getOpen(b[3]))
+	if b[4]%2 == 1 {
+		s = s.Complement()
+	}
+	return s
+}
+
+// Fuzz the boolean operations against a brute-force oracle, which checks
+// membership at every value getValue produces and between each of them.
+func FuzzBooleanOperations(f *testing.F) {
+	f.Add([]byte{1, 0, 3, 1, 0, 4, 1, 6, 0, 0, 2, 0, 5, 1, 1, 0, 0, 7, 0, 0})
+	f.Add([]byte{3, 0, 3, 0, 0, 3, 1, 5, 0, 1, 3, 0, 3, 0, 1, 1, 1, 3, 1, 0})
+	f.Add([]byte{0, 0, 7, 0, 1, 2, 1, 2, 1, 0, 2, 0, 4, 0, 0, 4, 0, 6, 0, 1})
+	f.Fuzz(func(t *testing.T, b []byte) {
+		if len(b) < 20 {
+			t.SkipNow()
+		}
+		a := fuzzSet(b[0:5]).Union(fuzzSet(b[5:10]))
+		c := fuzzSet(b[10:15]).Intersection(fuzzSet(b[15:20]))
+
+		ops := map[string]struct {
+			s  Set
+			fn func(bool, bool) bool
+		}{
+			"union":                {a.Union(c), func(x, y bool) bool { return x || y }},
+			"intersection":         {a.Intersection(c), func(x, y bool) bool { return x && y }},
+			"difference":           {a.Difference(c), func(x, y bool) bool { return x && !y }},
+			"symmetric difference": {a.SymmetricDifference(c), func(x, y bool) bool { return x != y }},
+		}
+
+		probes := []string{"-1", "0", "1", "2", "2.5", "3", "3.5", "4", "4.5", "5", "5.5", "6", "7"}
+		for name, op := range ops {
+			if err := op.s.Validate(); err != nil {
+				t.Fatalf("%v: %v", name, err)
+			}
+			for _, p := range probes {
+				x := decimal(p)
+				inA := oracleContains(b[0:5], x) || oracleContains(b[5:10], x)
+				inC := oracleContains(b[10:15], x) && oracleContains(b[15:20], x)
+				if op.s.Contains(x) != op.fn(inA, inC) {
+					t.Fatalf("%v of '%v' and '%v' is '%v', which wrongly includes or excludes %v", name, a.String(), c.String(), op.s.String(), p)
+				}
+			}
+		}
+	})
+}
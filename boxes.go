@@ -0,0 +1,225 @@
+package apis
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/cockroachdb/apd/v3"
+)
+
+// Box is an axis-aligned product of one Set per dimension.
+type Box []Set
+
+// BoxSet is a finite union of boxes in N dimensions, such as a price x
+// quantity constraint region.
+//
+// It is kept in a canonical form, built recursively from the first axis: the
+// first axis is split into disjoint slabs, each holding every coordinate
+// whose cross-section through the remaining axes is the same, with slabs
+// having equal cross-sections merged. Two BoxSets with the same points
+// therefore have the same slabs.
+type BoxSet struct {
+	dims int
+	// full records, for zero dimensions, whether the set holds the one point.
+	full  bool
+	slabs []slab
+}
+
+type slab struct {
+	axis Set
+	rest BoxSet
+}
+
+// NewBox returns the box with the given sides, one per dimension.
+func NewBox(sides ...Set) BoxSet {
+	if len(sides) == 0 {
+		return BoxSet{full: true}
+	}
+	rest := NewBox(sides[1:]...)
+	if sides[0].IsEmpty() || rest.IsEmpty() {
+		return BoxSet{dims: len(sides)}
+	}
+	return BoxSet{dims: len(sides), slabs: []slab{{sides[0].Clone(), rest}}}
+}
+
+// EmptyBoxSet returns the empty set in the given number of dimensions.
+func EmptyBoxSet(dims int) BoxSet {
+	return BoxSet{dims: dims}
+}
+
+func (a BoxSet) Dims() int {
+	return a.dims
+}
+
+func (a BoxSet) IsEmpty() bool {
+	if a.dims == 0 {
+		return !a.full
+	}
+	return len(a.slabs) == 0
+}
+
+// Union panics if a and b have different dimensions, as do Intersection and
+// Difference.
+func (a BoxSet) Union(b BoxSet) BoxSet {
+	return combineBoxes(a, b, func(x, y bool) bool { return x || y })
+}
+
+func (a BoxSet) Intersection(b BoxSet) BoxSet {
+	return combineBoxes(a, b, func(x, y bool) bool { return x && y })
+}
+
+func (a BoxSet) Difference(b BoxSet) BoxSet {
+	return combineBoxes(a, b, func(x, y bool) bool { return x && !y })
+}
+
+// combineBoxes evaluates op over every region of the first axis where the
+// cross-sections of a and b are fixed, recursing into the remaining axes.
+// op(false, false) must be false.
+func combineBoxes(a, b BoxSet, op func(bool, bool) bool) BoxSet {
+	if a.dims != b.dims {
+		panic(fmt.Sprintf("box sets have different dimensions %v and %v", a.dims, b.dims))
+	}
+	if a.dims == 0 {
+		return BoxSet{full: op(a.full, b.full)}
+	}
+
+	aAll, bAll := Set{}, Set{}
+	for _, s := range a.slabs {
+		aAll = aAll.Union(s.axis)
+	}
+	for _, s := range b.slabs {
+		bAll = bAll.Union(s.axis)
+	}
+	empty := EmptyBoxSet(a.dims - 1)
+	aOnly, bOnly := bAll.Complement(), aAll.Complement()
+
+	parts := []slab{}
+	for _, sa := range a.slabs {
+		parts = append(parts, slab{sa.axis.Intersection(aOnly), combineBoxes(sa.rest, empty, op)})
+		for _, sb := range b.slabs {
+			parts = append(parts, slab{sa.axis.Intersection(sb.axis), combineBoxes(sa.rest, sb.rest, op)})
+		}
+	}
+	for _, sb := range b.slabs {
+		parts = append(parts, slab{sb.axis.Intersection(bOnly), combineBoxes(empty, sb.rest, op)})
+	}
+	return fromSlabs(a.dims, parts)
+}
+
+// fromSlabs builds the canonical form from slabs with disjoint axes, merging
+// those with equal cross-sections.
+func fromSlabs(dims int, parts []slab) BoxSet {
+	res := BoxSet{dims: dims}
+outer:
+	for _, p := range parts {
+		if p.axis.IsEmpty() || p.rest.IsEmpty() {
+			continue
+		}
+		for i := range res.slabs {
+			if res.slabs[i].rest.Equal(p.rest) {
+				res.slabs[i].axis = res.slabs[i].axis.Union(p.axis)
+				continue outer
+			}
+		}
+		res.slabs = append(res.slabs, p)
+	}
+
+	// Order slabs by where they start. As their axes are disjoint, two slabs
+	// can only start at the same number if one of them includes it.
+	sort.Slice(res.slabs, func(i, j int) bool {
+		x, y := res.slabs[i].axis.items[0], res.slabs[j].axis.items[0]
+		if c := x.d.Cmp(&y.d); c != 0 {
+			return c < 0
+		}
+		return res.slabs[i].axis.Contains(x.d)
+	})
+	return res
+}
+
+// Equal reports whether a and b contain the same points.
+func (a BoxSet) Equal(b BoxSet) bool {
+	if a.dims != b.dims || a.full != b.full || len(a.slabs) != len(b.slabs) {
+		return false
+	}
+	for i := range a.slabs {
+		if !a.slabs[i].axis.Equal(b.slabs[i].axis) || !a.slabs[i].rest.Equal(b.slabs[i].rest) {
+			return false
+		}
+	}
+	return true
+}
+
+// Contains reports whether the point, given as one coordinate per dimension,
+// is in the set.
+func (a BoxSet) Contains(point ...apd.Decimal) bool {
+	if len(point) != a.dims {
+		panic(fmt.Sprintf("point has %v coordinates, but box set has %v dimensions", len(point), a.dims))
+	}
+	if a.dims == 0 {
+		return a.full
+	}
+	for _, s := range a.slabs {
+		if s.axis.Contains(point[0]) {
+			return s.rest.Contains(point[1:]...)
+		}
+	}
+	return false
+}
+
+// Volume returns the total N-dimensional volume, which is Infinity if the set
+// has an unbounded box of non-zero volume. Boxes that are flat along any axis
+// have no volume, even if unbounded along others.
+func (a BoxSet) Volume() apd.Decimal {
+	if a.dims == 0 {
+		if a.full {
+			return *apd.New(1, 0)
+		}
+		return apd.Decimal{}
+	}
+	var total apd.Decimal
+	for _, s := range a.slabs {
+		m, v := s.axis.Measure(), s.rest.Volume()
+		if m.IsZero() || v.IsZero() {
+			continue
+		}
+		var product apd.Decimal
+		_, _ = apd.BaseContext.Mul(&product, &m, &v)
+		_, _ = apd.BaseContext.Add(&total, &total, &product)
+	}
+	return total
+}
+
+// Boxes returns the canonical decomposition of the set into disjoint boxes.
+func (a BoxSet) Boxes() []Box {
+	if a.dims == 0 {
+		if a.full {
+			return []Box{{}}
+		}
+		return []Box{}
+	}
+	res := []Box{}
+	for _, s := range a.slabs {
+		for _, rest := range s.rest.Boxes() {
+			res = append(res, append(Box{s.axis.Clone()}, rest...))
+		}
+	}
+	return res
+}
+
+func (b Box) String() string {
+	sides := make([]string, len(b))
+	for i, s := range b {
+		sides[i] = "{" + s.String() + "}"
+	}
+	return strings.Join(sides, " x ")
+}
+
+// String renders the canonical decomposition, one box per line.
+func (a BoxSet) String() string {
+	boxes := []string{}
+	for _, b := range a.Boxes() {
+		boxes = append(boxes, b.String())
+	}
+	return strings.Join(boxes, "\n")
+}
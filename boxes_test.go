@@ -0,0 +1,104 @@
+package apis
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/apd/v3"
+)
+
+func newSquare(l, u string) BoxSet {
+	side := newFromBounds(boundDef{l, false}, boundDef{u, false})
+	return NewBox(side, side)
+}
+
+func TestBoxSetOperations(t *testing.T) {
+	a := newSquare("0", "2")
+	b := newSquare("1", "3")
+
+	type testcase struct {
+		name   string
+		s      BoxSet
+		result string
+		volume string
+	}
+
+	cases := []testcase{
+		{"a", a, "{[0, 2]} x {[0, 2]}", "4"},
+		{"union", a.Union(b), "{[0, 1)} x {[0, 2]}\n{[1, 2]} x {[0, 3]}\n{(2, 3]} x {[1, 3]}", "7"},
+		{"intersection", a.Intersection(b), "{[1, 2]} x {[1, 2]}", "1"},
+		{"difference", a.Difference(b), "{[0, 1)} x {[0, 2]}\n{[1, 2]} x {[0, 1)}", "3"},
+		{"disjoint", a.Intersection(newSquare("5", "6")), "", "0"},
+		{"unbounded", NewBox(newFromBounds(boundDef{"0", false}, boundDef{"infinity", true}), newFromBounds(boundDef{"0", false}, boundDef{"1", false})), "{[0, Infinity)} x {[0, 1]}", "Infinity"},
+		{"flat", NewBox(newFromBounds(boundDef{"0", false}, boundDef{"infinity", true}), newFromBounds(boundDef{"1", false}, boundDef{"1", false})), "{[0, Infinity)} x {[1, 1]}", "0"},
+		{"empty side", NewBox(Set{}, newFromBounds(boundDef{"0", false}, boundDef{"1", false})), "", "0"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			r := c.s.String()
+			if r != c.result {
+				t.Fatalf("Expected '%v', but got '%v'", c.result, r)
+			}
+			v := c.s.Volume()
+			if v.String() != c.volume {
+				t.Fatalf("Expected volume %v, but got %v", c.volume, v.String())
+			}
+		})
+	}
+}
+
+func TestBoxSetCanonical(t *testing.T) {
+	a := newSquare("0", "2")
+	b := newSquare("1", "3")
+
+	// The same region, assembled from different pieces.
+	left := NewBox(newFromBounds(boundDef{"0", false}, boundDef{"1", true}), newFromBounds(boundDef{"0", false}, boundDef{"2", false}))
+	right := NewBox(newFromBounds(boundDef{"1", false}, boundDef{"2", false}), newFromBounds(boundDef{"0", false}, boundDef{"2", false}))
+	if !left.Union(right).Equal(a) {
+		t.Fatalf("Expected '%v' to equal '%v'", left.Union(right), a)
+	}
+	if !a.Union(b).Difference(b).Equal(a.Difference(b)) {
+		t.Fatalf("Expected '%v' to equal '%v'", a.Union(b).Difference(b), a.Difference(b))
+	}
+	if !a.Difference(b).Union(a.Intersection(b)).Equal(a) {
+		t.Fatalf("Expected '%v' to equal '%v'", a.Difference(b).Union(a.Intersection(b)), a)
+	}
+	if !a.Difference(a).IsEmpty() {
+		t.Fatalf("Expected '%v' to be empty", a.Difference(a))
+	}
+
+	// Boxes are disjoint, so their volumes add up.
+	var total apd.Decimal
+	for _, box := range a.Union(b).Boxes() {
+		v := NewBox(box...).Volume()
+		_, _ = apd.BaseContext.Add(&total, &total, &v)
+	}
+	if total.String() != "7" {
+		t.Fatalf("Expected boxes to total 7, but got %v", total.String())
+	}
+}
+
+func TestBoxSetContains(t *testing.T) {
+	s := newSquare("0", "2").Union(newSquare("1", "3")).Difference(newSquare("1.5", "1.5"))
+
+	type testcase struct {
+		x, y     string
+		expected bool
+	}
+
+	cases := []testcase{
+		{"0", "0", true},
+		{"2.5", "2.5", true},
+		{"0.5", "2.5", false},
+		{"2.5", "0.5", false},
+		{"1.5", "1.5", false},
+		{"1.5", "1.6", true},
+		{"-1", "1", false},
+	}
+
+	for _, c := range cases {
+		if s.Contains(decimal(c.x), decimal(c.y)) != c.expected {
+			t.Fatalf("Expected Contains(%v, %v) to be %v", c.x, c.y, c.expected)
+		}
+	}
+}
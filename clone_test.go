@@ -0,0 +1,138 @@
+package apis
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/cockroachdb/apd/v3"
+)
+
+// Coefficients this large are too long to be stored within an apd.Decimal,
+// so copying the Decimal by value would share their digits.
+const (
+	hugeLower = "123456789012345678901234567890123456789012345678901234567890"
+	hugeUpper = "123456789012345678901234567890123456789012345678901234567899"
+	hugeMid   = "123456789012345678901234567890123456789012345678901234567895"
+)
+
+// scribble increments, in place, every decimal of s, as a caller doing
+// arithmetic on decimals it got from a set might.
+func scribble(s Set) {
+	for i := range s.items {
+		_, _ = apd.BaseContext.Add(&s.items[i].d, &s.items[i].d, apd.New(1, 0))
+	}
+}
+
+func TestOperationsShareNoMemory(t *testing.T) {
+	type testcase struct {
+		name string
+		fn   func(a, b Set) Set
+	}
+
+	cases := []testcase{
+		{"Union", Set.Union},
+		{"Intersection", Set.Intersection},
+		{"Difference", Set.Difference},
+		{"SymmetricDifference", Set.SymmetricDifference},
+		{"Complement", func(a, b Set) Set { return a.Complement() }},
+		{"Clone", func(a, b Set) Set { return a.Clone() }},
+		{"FromIntervals", func(a, b Set) Set { return FromIntervals(a.Intervals()...) }},
+		{"UnionAll", func(a, b Set) Set { return UnionAll(a, b) }},
+		{"IntersectAll", func(a, b Set) Set { return IntersectAll(a, b) }},
+		{"UnionAllParallel", func(a, b Set) Set { s, _ := UnionAllParallel(context.Background(), 2, a, b); return s }},
+		{"AtLeast", func(a, b Set) Set { return AtLeast(1, a, b) }},
+		{"Partition", func(a, b Set) Set { return Partition(a, b)[0].Set }},
+		{"UnionInto", func(a, b Set) Set { var s Set; UnionInto(&s, a, b); return s }},
+		{"UnionWith", func(a, b Set) Set { s := a.Clone(); s.UnionWith(b); return s }},
+		{"ComplementInPlace", func(a, b Set) Set { s := a.Clone(); s.ComplementInPlace(); return s }},
+		{"MutableSet", func(a, b Set) Set {
+			m := MutableSet{}
+			for _, iv := range a.Intervals() {
+				m.Add(iv)
+			}
+			return m.Snapshot()
+		}},
+		{"PersistentSet", func(a, b Set) Set { return NewPersistent(a).Union(NewPersistent(b)).Set() }},
+		{"ExtendedSet", func(a, b Set) Set { return Extend(a).Set() }},
+		{"BoundedSet", func(a, b Set) Set { return NewUniverse(a).All().Set() }},
+		{"BoxSet", func(a, b Set) Set { return NewBox(a, b).Boxes()[0][1] }},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			a := newFromBounds(boundDef{hugeLower, false}, boundDef{hugeUpper, true})
+			b := newFromBounds(boundDef{hugeMid, true}, boundDef{"infinity", true})
+			as, bs := a.String(), b.String()
+
+			r := c.fn(a, b)
+			rs := r.String()
+			scribble(r)
+			if a.String() != as || b.String() != bs {
+				t.Fatalf("Expected inputs '%v' and '%v' to be unchanged, but got '%v' and '%v'", as, bs, a.String(), b.String())
+			}
+
+			r = c.fn(a, b)
+			scribble(a)
+			scribble(b)
+			if r.String() != rs {
+				t.Fatalf("Expected result '%v' to be unchanged, but got '%v'", rs, r.String())
+			}
+		})
+	}
+}
+
+func TestConstructorsShareNoMemory(t *testing.T) {
+	l, u := decimal(hugeLower), decimal(hugeUpper)
+	s := New(l, false, u, false)
+	expected := s.String()
+	_, _ = apd.BaseContext.Add(&l, &l, apd.New(1, 0))
+	_, _ = apd.BaseContext.Add(&u, &u, apd.New(1, 0))
+	if r := s.String(); r != expected {
+		t.Fatalf("Expected '%v', but got '%v'", expected, r)
+	}
+
+	ivs := s.Intervals()
+	_, _ = apd.BaseContext.Add(&ivs[0].Lower, &ivs[0].Lower, apd.New(1, 0))
+	if r := s.String(); r != expected {
+		t.Fatalf("Expected '%v', but got '%v'", expected, r)
+	}
+
+	iv := Interval{Lower: decimal(hugeLower), Upper: decimal(hugeUpper)}
+	m := MutableSet{}
+	m.Add(iv)
+	_, _ = apd.BaseContext.Add(&iv.Upper, &iv.Upper, apd.New(1, 0))
+	snapshot := m.Snapshot()
+	if r := snapshot.String(); r != expected {
+		t.Fatalf("Expected '%v', but got '%v'", expected, r)
+	}
+}
+
+// TestConcurrentOperations is most useful under the race detector, which
+// reports any memory that operations on shared sets write to.
+func TestConcurrentOperations(t *testing.T) {
+	a := newFromBounds(boundDef{hugeLower, false}, boundDef{hugeUpper, true})
+	b := newFromBounds(boundDef{hugeMid, true}, boundDef{"infinity", true})
+	union := a.Union(b)
+	expected := union.String()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				r := a.Union(b)
+				if r.String() != expected {
+					t.Errorf("Expected '%v', but got '%v'", expected, r.String())
+					return
+				}
+				scribble(r)
+				s := a.Clone()
+				s.IntersectWith(b)
+				scribble(s)
+			}
+		}()
+	}
+	wg.Wait()
+}
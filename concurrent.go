@@ -0,0 +1,125 @@
+package apis
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/cockroachdb/apd/v3"
+)
+
+// ConcurrentSet is a Set shared between goroutines, such as a process-wide
+// allowlist, that is read far more often than it is updated. Reads load the
+// current snapshot atomically and never block; updates are serialized, and
+// replace the snapshot with a new one.
+//
+// The zero ConcurrentSet is empty and ready to use.
+type ConcurrentSet struct {
+	current atomic.Pointer[Set]
+
+	// mu serializes updates. Watchers are guarded by watchMu instead, so that
+	// a watcher can stop itself while an update is calling it.
+	mu       sync.Mutex
+	watchMu  sync.Mutex
+	watchers map[int]*watcher
+	next     int
+}
+
+type watcher struct {
+	fn      func(Change)
+	stopped atomic.Bool
+}
+
+// Change describes an update to a ConcurrentSet. Its sets are shared with
+// readers and other watchers, so must be cloned before being updated in place.
+type Change struct {
+	Old, New Set
+	// Added holds the numbers in New but not Old, and Removed the reverse.
+	Added, Removed Set
+}
+
+// NewConcurrentSet returns a ConcurrentSet holding s.
+func NewConcurrentSet(s Set) *ConcurrentSet {
+	c := &ConcurrentSet{}
+	s = s.Clone()
+	c.current.Store(&s)
+	return c
+}
+
+// Snapshot returns the current contents. The snapshot is shared with other
+// readers, so must be cloned before being updated in place.
+func (c *ConcurrentSet) Snapshot() Set {
+	if s := c.current.Load(); s != nil {
+		return *s
+	}
+	return Set{}
+}
+
+// Contains reports whether d is in the current contents.
+func (c *ConcurrentSet) Contains(d apd.Decimal) bool {
+	s := c.Snapshot()
+	return s.Contains(d)
+}
+
+// Update replaces the contents with fn applied to a copy of them, and then
+// tells every watcher of the change, if there is one. Updates are applied one
+// at a time, and fn must not itself update c.
+func (c *ConcurrentSet) Update(fn func(Set) Set) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	old := c.Snapshot()
+	s := fn(old.Clone())
+	if s.Equal(old) {
+		return
+	}
+	s = s.Clone()
+	c.current.Store(&s)
+
+	change := Change{Old: old, New: s}
+	change.Added, change.Removed = Diff(old, s)
+	c.watchMu.Lock()
+	watchers := make([]*watcher, 0, len(c.watchers))
+	for _, w := range c.watchers {
+		watchers = append(watchers, w)
+	}
+	c.watchMu.Unlock()
+	for _, w := range watchers {
+		if !w.stopped.Load() {
+			w.fn(change)
+		}
+	}
+}
+
+// Store replaces the contents with s.
+func (c *ConcurrentSet) Store(s Set) {
+	c.Update(func(Set) Set { return s })
+}
+
+// Watch calls fn with every later change, until the returned function is
+// called. Calls are made in the order of the changes, while the update that
+// made them is still in progress, so fn should be quick and must not update
+// c. It may watch c, or stop watching, including by calling its own stop
+// function.
+//
+// stop does not wait for a call to fn that is already in progress, as fn may
+// be the one calling it. An update that was dispatching to fn when stop was
+// called may therefore still call fn once, even after stop returns; updates
+// that start after stop returns never call it.
+func (c *ConcurrentSet) Watch(fn func(Change)) (stop func()) {
+	c.watchMu.Lock()
+	defer c.watchMu.Unlock()
+
+	if c.watchers == nil {
+		c.watchers = map[int]*watcher{}
+	}
+	id := c.next
+	c.next++
+	w := &watcher{fn: fn}
+	c.watchers[id] = w
+	return func() {
+		w.stopped.Store(true)
+		c.watchMu.Lock()
+		defer c.watchMu.Unlock()
+		delete(c.watchers, id)
+	}
+}
@@ -0,0 +1,164 @@
+package apis
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/apd/v3"
+)
+
+func TestConcurrentSetWatch(t *testing.T) {
+	c := &ConcurrentSet{}
+	changes := []string{}
+	stop := c.Watch(func(ch Change) {
+		changes = append(changes, ch.Old.String()+" -> "+ch.New.String()+": +"+ch.Added.String()+" -"+ch.Removed.String())
+	})
+
+	c.Store(newFromBounds(boundDef{"0", false}, boundDef{"10", false}))
+	c.Update(func(s Set) Set { return s.Difference(newFromBounds(boundDef{"5", false}, boundDef{"5", false})) })
+	c.Update(func(s Set) Set { return s.Union(newFromBounds(boundDef{"4", false}, boundDef{"6", false})) })
+	// Changes to nothing are not reported.
+	c.Update(func(s Set) Set { return s.Union(newFromBounds(boundDef{"1", false}, boundDef{"2", false})) })
+	c.Update(func(s Set) Set { s.UnionWith(newFromBounds(boundDef{"10", true}, boundDef{"20", false})); return s })
+	stop()
+	c.Store(Set{})
+
+	expected := []string{
+		" -> [0, 10]: +[0, 10] -",
+		"[0, 10] -> [0, 5), (5, 10]: + -[5, 5]",
+		"[0, 5), (5, 10] -> [0, 10]: +[5, 5] -",
+		"[0, 10] -> [0, 20]: +(10, 20] -",
+	}
+	if len(changes) != len(expected) {
+		t.Fatalf("Expected %v changes, but got %v", expected, changes)
+	}
+	for i := range expected {
+		if changes[i] != expected[i] {
+			t.Fatalf("Expected '%v', but got '%v'", expected[i], changes[i])
+		}
+	}
+	if s := c.Snapshot(); !s.IsEmpty() {
+		t.Fatalf("Expected the set to be empty, but got '%v'", s.String())
+	}
+}
+
+func TestConcurrentSetWatchStopsItself(t *testing.T) {
+	c := &ConcurrentSet{}
+	calls := 0
+	var stop func()
+	stop = c.Watch(func(Change) {
+		calls++
+		stop()
+	})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		c.Store(newFromBounds(boundDef{"0", false}, boundDef{"1", false}))
+		c.Store(newFromBounds(boundDef{"2", false}, boundDef{"3", false}))
+	}()
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("Expected a watcher to be able to stop itself, but the update deadlocked")
+	}
+	if calls != 1 {
+		t.Fatalf("Expected one call before the watcher stopped, but got %v", calls)
+	}
+}
+
+func TestConcurrentSetWatchStoppedDuringUpdate(t *testing.T) {
+	c := &ConcurrentSet{}
+	calls := make(chan Change, 2)
+	release := make(chan struct{})
+	stop := c.Watch(func(ch Change) {
+		calls <- ch
+		<-release
+	})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		c.Store(newFromBounds(boundDef{"0", false}, boundDef{"1", false}))
+	}()
+	<-calls
+
+	// Stopping from another goroutine while the update is calling the watcher
+	// does not wait for that call.
+	stopped := make(chan struct{})
+	go func() {
+		stop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(10 * time.Second):
+		t.Fatal("Expected stop not to wait for the update in progress")
+	}
+	close(release)
+	<-done
+
+	c.Store(newFromBounds(boundDef{"2", false}, boundDef{"3", false}))
+	select {
+	case ch := <-calls:
+		t.Fatalf("Expected no calls after stop returned, but got '%v'", ch.New.String())
+	default:
+	}
+}
+
+func TestConcurrentSetReaders(t *testing.T) {
+	// Every version holds [0, 1], and each update adds another interval.
+	c := NewConcurrentSet(newFromBounds(boundDef{"0", false}, boundDef{"1", false}))
+	seen := 0
+	c.Watch(func(ch Change) {
+		seen++
+		if !ch.Old.IsSubsetOf(ch.New) || ch.Added.IsEmpty() || !ch.Removed.IsEmpty() {
+			t.Errorf("Expected '%v' to add to '%v'", ch.New.String(), ch.Old.String())
+		}
+	})
+
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				if !c.Contains(decimal("0.5")) {
+					t.Errorf("Expected every version to contain 0.5")
+					return
+				}
+				s := c.Snapshot()
+				if err := s.Validate(); err != nil {
+					t.Error(err)
+					return
+				}
+			}
+		}()
+	}
+
+	var writers sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		writers.Add(1)
+		go func(i int) {
+			defer writers.Done()
+			for j := 0; j < 25; j++ {
+				n := *apd.New(int64(1000*i+10*j), 0)
+				c.Update(func(s Set) Set { return s.Union(New(n, false, n, false)) })
+			}
+		}(i + 1)
+	}
+	writers.Wait()
+	close(done)
+	wg.Wait()
+
+	if s := c.Snapshot(); len(s.Intervals()) != 101 || seen != 100 {
+		t.Fatalf("Expected 100 updates, but got %v intervals after %v changes", len(s.Intervals()), seen)
+	}
+}
@@ -0,0 +1,71 @@
+package apis
+
+import (
+	"sort"
+
+	"github.com/cockroachdb/apd/v3"
+)
+
+// Coverage returns the function giving, at each decimal, how many of the sets
+// contain it. It sweeps every set's items in a single sorted pass.
+func Coverage(sets ...Set) StepFunction {
+	events := sweepEvents(sets)
+
+	f := StepFunction{}
+	in := make([]bool, len(sets))
+	depth := int64(0)
+	for j := 0; j < len(events); {
+		d := events[j].i.d
+		at, after := depth, depth
+		// Each set has at most one item at d.
+		for ; j < len(events) && events[j].i.d.Cmp(&d) == 0; j++ {
+			e := events[j]
+			inAt, inAfter := e.i.step(in[e.set])
+			at += count(inAt) - count(in[e.set])
+			after += count(inAfter) - count(in[e.set])
+			in[e.set] = inAfter
+		}
+		depth = after
+
+		switch {
+		case d.Form != apd.Infinite:
+			f.breaks = appendBreakpoint(f.breaks, f.valueBefore(len(f.breaks)), d, *apd.New(at, 0), *apd.New(after, 0))
+		case d.Negative:
+			f.below = *apd.New(after, 0)
+		}
+	}
+	return f
+}
+
+func count(in bool) int64 {
+	if in {
+		return 1
+	}
+	return 0
+}
+
+// sweepEvent is an item of the set-th set.
+type sweepEvent struct {
+	set int
+	i   item
+}
+
+// sweepEvents merges every set's items into one list sorted by decimal. Each
+// set has at most one item at any decimal.
+func sweepEvents(sets []Set) []sweepEvent {
+	events := []sweepEvent{}
+	for n, s := range sets {
+		for _, i := range s.items {
+			events = append(events, sweepEvent{n, i})
+		}
+	}
+	sort.SliceStable(events, func(x, y int) bool {
+		return events[x].i.d.Cmp(&events[y].i.d) < 0
+	})
+	return events
+}
+
+// AtLeast returns the decimals contained in at least k of the sets.
+func AtLeast(k int, sets ...Set) Set {
+	return Coverage(sets...).AtLeast(*apd.New(int64(k), 0))
+}
@@ -0,0 +1,71 @@
+package apis
+
+import (
+	"testing"
+)
+
+func TestCoverage(t *testing.T) {
+	a := newFromBounds(boundDef{"0", false}, boundDef{"10", true})
+	b := newFromBounds(boundDef{"5", true}, boundDef{"15", false})
+	c := newFromBounds(boundDef{"10", false}, boundDef{"10", false})
+	d := newFromBounds(boundDef{"7", false}, boundDef{"7", false}).Complement()
+
+	type testcase struct {
+		sets   []Set
+		result string
+	}
+
+	cases := []testcase{
+		{[]Set{}, "(-Infinity, Infinity): 0"},
+		{[]Set{a}, "(-Infinity, 0): 0, [0, 10): 1, [10, Infinity): 0"},
+		{[]Set{a, b}, "(-Infinity, 0): 0, [0, 5]: 1, (5, 10): 2, [10, 15]: 1, (15, Infinity): 0"},
+		{[]Set{a, b, c}, "(-Infinity, 0): 0, [0, 5]: 1, (5, 10]: 2, (10, 15]: 1, (15, Infinity): 0"},
+		{[]Set{a, d}, "(-Infinity, 0): 1, [0, 7): 2, [7, 7]: 1, (7, 10): 2, [10, Infinity): 1"},
+		{[]Set{d, d}, "(-Infinity, 7): 2, [7, 7]: 0, (7, Infinity): 2"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.result, func(t *testing.T) {
+			r := Coverage(c.sets...).String()
+			if r != c.result {
+				t.Fatalf("Expected '%v', but got '%v'", c.result, r)
+			}
+		})
+	}
+}
+
+func TestAtLeast(t *testing.T) {
+	a := newFromBounds(boundDef{"0", false}, boundDef{"10", true})
+	b := newFromBounds(boundDef{"5", true}, boundDef{"15", false})
+	c := newFromBounds(boundDef{"10", false}, boundDef{"20", false})
+
+	type testcase struct {
+		k      int
+		result string
+	}
+
+	cases := []testcase{
+		{0, "(-Infinity, Infinity)"},
+		{1, "[0, 20]"},
+		{2, "(5, 15]"},
+		{3, ""},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.result, func(t *testing.T) {
+			s := AtLeast(tc.k, a, b, c)
+			if err := s.Validate(); err != nil {
+				t.Fatal(err)
+			}
+			if r := s.String(); r != tc.result {
+				t.Fatalf("Expected '%v', but got '%v'", tc.result, r)
+			}
+		})
+	}
+
+	// At least one is the union, and at least all is the intersection.
+	union, all := AtLeast(1, a, b), AtLeast(2, a, b)
+	if !union.Equal(a.Union(b)) || !all.Equal(a.Intersection(b)) {
+		t.Fatalf("Expected '%v' and '%v' to be the union and intersection", union.String(), all.String())
+	}
+}
@@ -0,0 +1,166 @@
+package apis
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cockroachdb/apd/v3"
+)
+
+// CyclicSet is a set of points on a circle of circumference period, such as
+// compass headings or times of day. Intervals may wrap around, as in 350° to
+// 10°, and are normalized into a Set within [0, period).
+type CyclicSet struct {
+	period apd.Decimal
+	s      Set
+}
+
+// NewCyclic returns the arc from l, increasing, to u on a circle of
+// circumference period. Bounds may lie outside [0, period), and u may be
+// below l, in which case the arc wraps around. An arc at least period long
+// covers the whole circle, apart from its endpoint if both bounds are open
+// and it is exactly period long.
+func NewCyclic(period apd.Decimal, l apd.Decimal, lOpen bool, u apd.Decimal, uOpen bool) (CyclicSet, error) {
+	if period.Form != apd.Finite || period.Sign() <= 0 {
+		return CyclicSet{}, fmt.Errorf("period %v must be finite and positive", &period)
+	}
+	if l.Form != apd.Finite || u.Form != apd.Finite {
+		return CyclicSet{}, fmt.Errorf("arc from %v to %v must have finite bounds", &l, &u)
+	}
+
+	var length apd.Decimal
+	_, _ = apd.BaseContext.Sub(&length, &u, &l)
+	if length.Negative {
+		length = modPeriod(length, period)
+	}
+	start := modPeriod(l, period)
+	c := CyclicSet{period: period}
+
+	switch length.Cmp(&period) {
+	case 1:
+		c.s = c.all()
+		return c, nil
+	case 0:
+		c.s = c.all()
+		if lOpen && uOpen {
+			c.s = c.s.Intersection(New(start, false, start, false).Complement())
+		}
+		return c, nil
+	}
+
+	if length.IsZero() && (lOpen || uOpen) {
+		return c, nil
+	}
+	var end apd.Decimal
+	_, _ = apd.BaseContext.Add(&end, &start, &length)
+	switch end.Cmp(&period) {
+	case -1:
+		c.s = New(start, lOpen, end, uOpen)
+	case 0:
+		// The arc ends at zero.
+		c.s = New(start, lOpen, period, true)
+		if !uOpen {
+			c.s = c.s.Union(New(decimalZero, false, decimalZero, false))
+		}
+	case 1:
+		// The arc wraps past zero.
+		_, _ = apd.BaseContext.Sub(&end, &end, &period)
+		c.s = New(start, lOpen, period, true).Union(New(decimalZero, false, end, uOpen))
+	}
+	return c, nil
+}
+
+var decimalZero = apd.Decimal{}
+
+// all returns [0, period).
+func (c CyclicSet) all() Set {
+	return New(decimalZero, false, c.period, true)
+}
+
+// modPeriod returns d modulo period, in [0, period).
+func modPeriod(d apd.Decimal, period apd.Decimal) apd.Decimal {
+	q := ratFromDecimal(d)
+	k, _ := floorRat(q.Quo(q, ratFromDecimal(period)))
+	kd := decimalFromBigInt(k)
+	var res apd.Decimal
+	_, _ = apd.BaseContext.Mul(&res, &period, &kd)
+	_, _ = apd.BaseContext.Sub(&res, &d, &res)
+	return res
+}
+
+func (a CyclicSet) check(b CyclicSet) {
+	if a.period.Cmp(&b.period) != 0 {
+		panic(fmt.Sprintf("cyclic sets have different periods %v and %v", &a.period, &b.period))
+	}
+}
+
+// Union panics if a and b have different periods, as do the other binary
+// operations.
+func (a CyclicSet) Union(b CyclicSet) CyclicSet {
+	a.check(b)
+	return CyclicSet{a.period, a.s.Union(b.s)}
+}
+
+func (a CyclicSet) Intersection(b CyclicSet) CyclicSet {
+	a.check(b)
+	return CyclicSet{a.period, a.s.Intersection(b.s)}
+}
+
+// Complement returns the rest of the circle.
+func (a CyclicSet) Complement() CyclicSet {
+	return CyclicSet{a.period, a.s.Complement().Intersection(a.all())}
+}
+
+// Contains reports whether d, taken modulo the period, is in the set.
+func (a CyclicSet) Contains(d apd.Decimal) bool {
+	if d.Form != apd.Finite {
+		return false
+	}
+	return a.s.Contains(modPeriod(d, a.period))
+}
+
+func (a CyclicSet) IsEmpty() bool {
+	return a.s.IsEmpty()
+}
+
+// Set returns the set normalized into [0, period).
+func (a CyclicSet) Set() Set {
+	return a.s.Clone()
+}
+
+// String renders the set as arcs, joining an arc that wraps around the end
+// of the period back into one, like "[350, 10]".
+func (a CyclicSet) String() string {
+	ivs := a.s.Intervals()
+	n := len(ivs)
+	if n < 2 || !ivs[0].Lower.IsZero() || ivs[0].LowerOpen || ivs[n-1].Upper.Cmp(&a.period) != 0 {
+		return a.s.String()
+	}
+	if ivs[n-1].Lower.Cmp(&ivs[0].Upper) == 0 {
+		// The circle less a point, which would read as an empty arc if joined.
+		return a.s.String()
+	}
+
+	wrapped := Interval{
+		Lower:     ivs[n-1].Lower,
+		LowerOpen: ivs[n-1].LowerOpen,
+		Upper:     ivs[0].Upper,
+		UpperOpen: ivs[0].UpperOpen,
+	}
+	parts := []string{}
+	for _, iv := range ivs[1 : n-1] {
+		parts = append(parts, formatInterval(iv))
+	}
+	return strings.Join(append(parts, formatInterval(wrapped)), ", ")
+}
+
+func formatInterval(iv Interval) string {
+	l, u := "[", "]"
+	if iv.LowerOpen {
+		l = "("
+	}
+	if iv.UpperOpen {
+		u = ")"
+	}
+	return fmt.Sprintf("%v%v, %v%v", l, &iv.Lower, &iv.Upper, u)
+}
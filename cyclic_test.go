@@ -0,0 +1,109 @@
+package apis
+
+import (
+	"testing"
+)
+
+func newArc(t *testing.T, period string, b1 boundDef, b2 boundDef) CyclicSet {
+	c, err := NewCyclic(decimal(period), decimal(b1.s), b1.o, decimal(b2.s), b2.o)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := c.Set()
+	if err := s.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestNewCyclic(t *testing.T) {
+	type testcase struct {
+		b      []boundDef
+		result string
+	}
+
+	cases := []testcase{
+		{[]boundDef{{"10", false}, {"20", true}}, "[10, 20)"},
+		{[]boundDef{{"350", false}, {"10", false}}, "[350, 10]"},
+		{[]boundDef{{"350", true}, {"10", true}}, "(350, 10)"},
+		{[]boundDef{{"-10", false}, {"10", false}}, "[350, 10]"},
+		{[]boundDef{{"710", false}, {"730", false}}, "[350, 10]"},
+		{[]boundDef{{"350", false}, {"360", false}}, "[350, 0]"},
+		{[]boundDef{{"350", false}, {"360", true}}, "[350, 360)"},
+		{[]boundDef{{"0", false}, {"360", true}}, "[0, 360)"},
+		{[]boundDef{{"90", true}, {"450", true}}, "[0, 90), (90, 360)"},
+		{[]boundDef{{"0", false}, {"1000", false}}, "[0, 360)"},
+		{[]boundDef{{"45", false}, {"45", false}}, "[45, 45]"},
+		{[]boundDef{{"45", true}, {"45", false}}, ""},
+		{[]boundDef{{"-0.5", false}, {"0.5", true}}, "[359.5, 0.5)"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.result, func(t *testing.T) {
+			r := newArc(t, "360", c.b[0], c.b[1]).String()
+			if r != c.result {
+				t.Fatalf("Expected '%v', but got '%v'", c.result, r)
+			}
+		})
+	}
+
+	if _, err := NewCyclic(decimal("0"), decimal("1"), false, decimal("2"), false); err == nil {
+		t.Fatalf("Expected a zero period to be rejected")
+	}
+}
+
+func TestCyclicOperations(t *testing.T) {
+	night := newArc(t, "24", boundDef{"22", false}, boundDef{"6", true})
+	early := newArc(t, "24", boundDef{"4", false}, boundDef{"8", true})
+	late := newArc(t, "24", boundDef{"23", false}, boundDef{"1", true})
+
+	type testcase struct {
+		result string
+		s      CyclicSet
+	}
+
+	cases := []testcase{
+		{"[22, 6)", night},
+		{"[22, 8)", night.Union(early)},
+		{"[4, 6)", night.Intersection(early)},
+		{"[23, 1)", night.Intersection(late)},
+		{"[6, 22)", night.Complement()},
+		{"[1, 23)", late.Complement()},
+		{"[22, 6)", night.Complement().Complement()},
+		{"", night.Intersection(night.Complement())},
+		{"[0, 24)", night.Union(night.Complement())},
+	}
+
+	for _, c := range cases {
+		t.Run(c.result, func(t *testing.T) {
+			s := c.s.Set()
+			if err := s.Validate(); err != nil {
+				t.Fatal(err)
+			}
+			r := c.s.String()
+			if r != c.result {
+				t.Fatalf("Expected '%v', but got '%v'", c.result, r)
+			}
+		})
+	}
+
+	type membership struct {
+		d  string
+		in bool
+	}
+	for _, m := range []membership{
+		{"22", true},
+		{"23.5", true},
+		{"0", true},
+		{"24", true},
+		{"-1", true},
+		{"5.99", true},
+		{"6", false},
+		{"30", false},
+		{"12", false},
+	} {
+		if night.Contains(decimal(m.d)) != m.in {
+			t.Fatalf("Expected membership of %v in '%v' to be %v", m.d, night.String(), m.in)
+		}
+	}
+}
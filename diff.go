@@ -0,0 +1,32 @@
+package apis
+
+// Diff returns the numbers added to and removed from old to make new.
+func Diff(old, new Set) (added, removed Set) {
+	return new.Difference(old), old.Difference(new)
+}
+
+// Patch records a change to a set as the intervals added and removed, so
+// that it can be logged, or serialized, as with encoding/json, and replayed
+// elsewhere.
+type Patch struct {
+	Added   []Interval
+	Removed []Interval
+}
+
+// NewPatch returns the patch that turns old into new.
+func NewPatch(old, new Set) Patch {
+	added, removed := Diff(old, new)
+	return Patch{Added: added.Intervals(), Removed: removed.Intervals()}
+}
+
+// IsEmpty reports whether the patch changes nothing.
+func (p Patch) IsEmpty() bool {
+	return len(p.Added) == 0 && len(p.Removed) == 0
+}
+
+// Apply returns s with the patch's removals and then its additions made. For
+// the patch from old to new, Apply(old) is Equal to new, and as sets have one
+// canonical form, identical to it.
+func (p Patch) Apply(s Set) Set {
+	return s.Difference(FromIntervals(p.Removed...)).Union(FromIntervals(p.Added...))
+}
@@ -0,0 +1,77 @@
+package apis
+
+import (
+	"encoding/json"
+	"math/rand"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	type testcase struct {
+		old, new       Set
+		added, removed string
+	}
+
+	cases := []testcase{
+		{Set{}, Set{}, "", ""},
+		{Set{}, newFromBounds(boundDef{"0", false}, boundDef{"1", false}), "[0, 1]", ""},
+		{newFromBounds(boundDef{"0", false}, boundDef{"10", false}), newFromBounds(boundDef{"5", true}, boundDef{"15", false}), "(10, 15]", "[0, 5]"},
+		{newFromBounds(boundDef{"0", false}, boundDef{"10", false}), newFromBounds(boundDef{"0", true}, boundDef{"10", true}), "", "[0, 0], [10, 10]"},
+		{newFromBounds(boundDef{"5", false}, boundDef{"5", false}).Complement(), newFromBounds(boundDef{"-infinity", true}, boundDef{"infinity", true}), "[5, 5]", ""},
+	}
+
+	for _, c := range cases {
+		t.Run(c.added+" "+c.removed, func(t *testing.T) {
+			added, removed := Diff(c.old, c.new)
+			if r := added.String(); r != c.added {
+				t.Fatalf("Expected added '%v', but got '%v'", c.added, r)
+			}
+			if r := removed.String(); r != c.removed {
+				t.Fatalf("Expected removed '%v', but got '%v'", c.removed, r)
+			}
+		})
+	}
+}
+
+func TestPatchApply(t *testing.T) {
+	r := rand.New(rand.NewSource(3))
+	sets := randomSets(40, 5)
+	random := func() Set {
+		s := Set{}
+		for i := 0; i < 4; i++ {
+			s = s.Union(sets[r.Intn(len(sets))])
+		}
+		if r.Intn(3) == 0 {
+			s = s.Complement()
+		}
+		return s
+	}
+
+	for n := 0; n < 200; n++ {
+		old, new := random(), random()
+		p := NewPatch(old, new)
+
+		// Round trip the patch, as a replica receiving it would.
+		b, err := json.Marshal(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var replayed Patch
+		if err := json.Unmarshal(b, &replayed); err != nil {
+			t.Fatal(err)
+		}
+
+		for _, q := range []Patch{p, replayed} {
+			s := q.Apply(old)
+			if err := s.Validate(); err != nil {
+				t.Fatal(err)
+			}
+			if !s.Equal(new) || s.String() != new.String() {
+				t.Fatalf("Expected patch %s applied to '%v' to give '%v', but got '%v'", b, old.String(), new.String(), s.String())
+			}
+		}
+		if p.IsEmpty() != old.Equal(new) {
+			t.Fatalf("Expected the patch from '%v' to '%v' to be empty only if they are equal", old.String(), new.String())
+		}
+	}
+}
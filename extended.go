@@ -0,0 +1,114 @@
+package apis
+
+import (
+	"strings"
+
+	"github.com/cockroachdb/apd/v3"
+)
+
+// ExtendedSet is a set of extended reals, in which -Infinity and Infinity
+// are attainable values that may be members, as with IEEE overflow results.
+//
+// The finite members are held in a Set, with the membership of each infinity
+// tracked alongside it, since Set itself only uses infinities as open bounds.
+type ExtendedSet struct {
+	s                                  Set
+	negativeInfinity, positiveInfinity bool
+}
+
+// NewExtended returns the extended reals between l and u. Unlike New, an
+// infinite bound may be closed, making that infinity a member.
+func NewExtended(l apd.Decimal, lOpen bool, u apd.Decimal, uOpen bool) ExtendedSet {
+	if l.Cmp(&u) > 0 {
+		l, lOpen, u, uOpen = u, uOpen, l, lOpen
+	}
+	e := ExtendedSet{s: New(l, lOpen, u, uOpen)}
+	if l.Cmp(&u) == 0 {
+		// New does not distinguish open and closed points.
+		if lOpen || uOpen {
+			e.s = Set{}
+		}
+		lOpen, uOpen = lOpen || uOpen, lOpen || uOpen
+	}
+	e.negativeInfinity = isNegativeInfinity(l) && !lOpen
+	e.positiveInfinity = isPositiveInfinity(u) && !uOpen
+	return e
+}
+
+// Extend returns the extended reals in s, which excludes both infinities.
+func Extend(s Set) ExtendedSet {
+	return ExtendedSet{s: s.Clone()}
+}
+
+// Set returns the finite members of a.
+func (a ExtendedSet) Set() Set {
+	return a.s.Clone()
+}
+
+func (a ExtendedSet) Union(b ExtendedSet) ExtendedSet {
+	return ExtendedSet{
+		s:                a.s.Union(b.s),
+		negativeInfinity: a.negativeInfinity || b.negativeInfinity,
+		positiveInfinity: a.positiveInfinity || b.positiveInfinity,
+	}
+}
+
+func (a ExtendedSet) Intersection(b ExtendedSet) ExtendedSet {
+	return ExtendedSet{
+		s:                a.s.Intersection(b.s),
+		negativeInfinity: a.negativeInfinity && b.negativeInfinity,
+		positiveInfinity: a.positiveInfinity && b.positiveInfinity,
+	}
+}
+
+func (a ExtendedSet) Complement() ExtendedSet {
+	return ExtendedSet{
+		s:                a.s.Complement(),
+		negativeInfinity: !a.negativeInfinity,
+		positiveInfinity: !a.positiveInfinity,
+	}
+}
+
+func (a ExtendedSet) Contains(d apd.Decimal) bool {
+	switch {
+	case isNegativeInfinity(d):
+		return a.negativeInfinity
+	case isPositiveInfinity(d):
+		return a.positiveInfinity
+	}
+	return a.s.Contains(d)
+}
+
+func (a ExtendedSet) IsEmpty() bool {
+	return a.s.IsEmpty() && !a.negativeInfinity && !a.positiveInfinity
+}
+
+func (a ExtendedSet) Equal(b ExtendedSet) bool {
+	return a.s.Equal(b.s) && a.negativeInfinity == b.negativeInfinity && a.positiveInfinity == b.positiveInfinity
+}
+
+// String renders member infinities as closed bounds, like "[-Infinity, 0]",
+// or as points when the adjoining finite numbers are not members.
+func (a ExtendedSet) String() string {
+	s := a.s.String()
+	n := len(a.s.items)
+	if a.negativeInfinity {
+		if n > 0 && isNegativeInfinity(a.s.items[0].d) {
+			s = "[" + strings.TrimPrefix(s, "(")
+		} else if s == "" {
+			s = "[-Infinity, -Infinity]"
+		} else {
+			s = "[-Infinity, -Infinity], " + s
+		}
+	}
+	if a.positiveInfinity {
+		if n > 0 && isPositiveInfinity(a.s.items[n-1].d) {
+			s = strings.TrimSuffix(s, ")") + "]"
+		} else if s == "" {
+			s = "[Infinity, Infinity]"
+		} else {
+			s = s + ", [Infinity, Infinity]"
+		}
+	}
+	return s
+}
@@ -0,0 +1,73 @@
+package apis
+
+import (
+	"testing"
+)
+
+func newExtendedFromBounds(b1 boundDef, b2 boundDef) ExtendedSet {
+	return NewExtended(decimal(b1.s), b1.o, decimal(b2.s), b2.o)
+}
+
+func TestExtendedCreationAndComplement(t *testing.T) {
+	type testcase struct {
+		b1               boundDef
+		b2               boundDef
+		creationResult   string
+		complementResult string
+	}
+	cases := []testcase{
+		{boundDef{"-infinity", false}, boundDef{"0", false}, "[-Infinity, 0]", "(0, Infinity]"},
+		{boundDef{"-infinity", true}, boundDef{"0", false}, "(-Infinity, 0]", "[-Infinity, -Infinity], (0, Infinity]"},
+		{boundDef{"-infinity", false}, boundDef{"infinity", false}, "[-Infinity, Infinity]", ""},
+		{boundDef{"-infinity", true}, boundDef{"infinity", true}, "(-Infinity, Infinity)", "[-Infinity, -Infinity], [Infinity, Infinity]"},
+		{boundDef{"infinity", false}, boundDef{"infinity", false}, "[Infinity, Infinity]", "[-Infinity, Infinity)"},
+		{boundDef{"infinity", true}, boundDef{"infinity", false}, "", "[-Infinity, Infinity]"},
+		{boundDef{"3", false}, boundDef{"3", false}, "[3, 3]", "[-Infinity, 3), (3, Infinity]"},
+		{boundDef{"infinity", false}, boundDef{"1", true}, "(1, Infinity]", "[-Infinity, 1]"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.creationResult, func(t *testing.T) {
+			s := newExtendedFromBounds(c.b1, c.b2)
+			fin := s.Set()
+			if err := fin.Validate(); err != nil {
+				t.Fatal(err)
+			}
+			r := s.String()
+			if r != c.creationResult {
+				t.Fatalf("Expected creation '%v', but got '%v'", c.creationResult, r)
+			}
+
+			comp := s.Complement()
+			r = comp.String()
+			if r != c.complementResult {
+				t.Fatalf("Expected complement '%v', but got '%v'", c.complementResult, r)
+			}
+			if !comp.Complement().Equal(s) {
+				t.Fatalf("Expected double complement to be '%v', but got '%v'", s.String(), comp.Complement().String())
+			}
+		})
+	}
+}
+
+func TestExtendedOperations(t *testing.T) {
+	a := newExtendedFromBounds(boundDef{"-infinity", false}, boundDef{"0", false})
+	b := newExtendedFromBounds(boundDef{"-5", false}, boundDef{"infinity", false})
+
+	if r := a.Union(b).String(); r != "[-Infinity, Infinity]" {
+		t.Fatalf("Unexpected union '%v'", r)
+	}
+	if r := a.Intersection(b).String(); r != "[-5, 0]" {
+		t.Fatalf("Unexpected intersection '%v'", r)
+	}
+	if r := Extend(a.Set()).Union(b).String(); r != "(-Infinity, Infinity]" {
+		t.Fatalf("Unexpected union '%v'", r)
+	}
+
+	if !a.Contains(decimal("-infinity")) || a.Contains(decimal("infinity")) || !a.Contains(decimal("-1")) {
+		t.Fatalf("Unexpected membership in '%v'", a.String())
+	}
+	if a.Intersection(b.Complement()).Intersection(a.Complement()).IsEmpty() != true {
+		t.Fatalf("Expected empty intersection")
+	}
+}
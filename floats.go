@@ -0,0 +1,133 @@
+package apis
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+
+	"github.com/cockroachdb/apd/v3"
+)
+
+// FromFloat64Interval returns the tightest closed decimal interval, with the
+// given number of significant digits, that contains every real number from
+// lo to hi. The binary values of lo and hi are used exactly, so the result
+// contains them even when their shortest decimal forms would not. Infinite
+// bounds are unbounded.
+func FromFloat64Interval(lo float64, hi float64, precision uint32) (Set, error) {
+	if math.IsNaN(lo) || math.IsNaN(hi) {
+		return Set{}, fmt.Errorf("cannot enclose NaN")
+	}
+	if lo > hi {
+		lo, hi = hi, lo
+	}
+
+	l, u := negativeInfinity, positiveInfinity
+	var err error
+	if !math.IsInf(lo, 0) {
+		l, err = decimalFromFloat(new(big.Rat).SetFloat64(lo), precision, apd.RoundFloor)
+		if err != nil {
+			return Set{}, err
+		}
+	} else if lo > 0 {
+		l = positiveInfinity
+	}
+	if !math.IsInf(hi, 0) {
+		u, err = decimalFromFloat(new(big.Rat).SetFloat64(hi), precision, apd.RoundCeiling)
+		if err != nil {
+			return Set{}, err
+		}
+	} else if hi < 0 {
+		u = negativeInfinity
+	}
+	return New(l, false, u, false), nil
+}
+
+// FromBigFloat returns the tightest closed decimal interval, with the given
+// number of significant digits, containing x. The interval is a single point
+// when x is exactly representable.
+func FromBigFloat(x *big.Float, precision uint32) (Set, error) {
+	if x.IsInf() {
+		return Set{}, fmt.Errorf("cannot enclose %v", x)
+	}
+	r, _ := x.Rat(nil)
+	l, err := decimalFromFloat(r, precision, apd.RoundFloor)
+	if err != nil {
+		return Set{}, err
+	}
+	u, err := decimalFromFloat(r, precision, apd.RoundCeiling)
+	if err != nil {
+		return Set{}, err
+	}
+	return New(l, false, u, false), nil
+}
+
+// decimalFromFloat rounds r as decimalFromRat does, but keeps rounded large
+// numbers in exponent notation, so that 1e300 becomes 1E+300 rather than 301
+// digits. Numbers that round to themselves are written out in full.
+func decimalFromFloat(r *big.Rat, precision uint32, rounding apd.Rounder) (apd.Decimal, error) {
+	d, err := roundRat(r, precision, rounding)
+	if err != nil {
+		return d, err
+	}
+	var res apd.Decimal
+	res.Reduce(&d)
+	if res.Exponent > 0 && d.Exponent <= 0 {
+		var scale apd.BigInt
+		scale.Exp(apd.NewBigInt(10), apd.NewBigInt(int64(res.Exponent)), nil)
+		res.Coeff.Mul(&res.Coeff, &scale)
+		res.Exponent = 0
+	}
+	return res, nil
+}
+
+// ToFloat64Bounds returns a closed float64 range enclosing each of the set's
+// intervals, rounding lower bounds down and upper bounds up.
+func (s Set) ToFloat64Bounds() [][2]float64 {
+	res := [][2]float64{}
+	for _, iv := range s.Intervals() {
+		res = append(res, [2]float64{float64Below(iv.Lower), float64Above(iv.Upper)})
+	}
+	return res
+}
+
+// float64Below returns the largest float64 not greater than d.
+func float64Below(d apd.Decimal) float64 {
+	if d.Form == apd.Infinite {
+		return math.Inf(d.Sign())
+	}
+	r := ratFromDecimal(d)
+	f, exact := r.Float64()
+	switch {
+	case exact:
+		return f
+	case math.IsInf(f, 1):
+		return math.MaxFloat64
+	case math.IsInf(f, -1):
+		return f
+	}
+	if new(big.Rat).SetFloat64(f).Cmp(r) > 0 {
+		f = math.Nextafter(f, math.Inf(-1))
+	}
+	return f
+}
+
+// float64Above returns the smallest float64 not less than d.
+func float64Above(d apd.Decimal) float64 {
+	if d.Form == apd.Infinite {
+		return math.Inf(d.Sign())
+	}
+	r := ratFromDecimal(d)
+	f, exact := r.Float64()
+	switch {
+	case exact:
+		return f
+	case math.IsInf(f, -1):
+		return -math.MaxFloat64
+	case math.IsInf(f, 1):
+		return f
+	}
+	if new(big.Rat).SetFloat64(f).Cmp(r) < 0 {
+		f = math.Nextafter(f, math.Inf(1))
+	}
+	return f
+}
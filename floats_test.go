@@ -0,0 +1,115 @@
+package apis
+
+import (
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/cockroachdb/apd/v3"
+)
+
+func TestFromFloat64Interval(t *testing.T) {
+	type testcase struct {
+		lo, hi    float64
+		precision uint32
+		result    string
+	}
+
+	cases := []testcase{
+		{0.1, 0.1, 20, "[0.10000000000000000555, 0.10000000000000000556]"},
+		{0.1, 0.2, 3, "[0.1, 0.201]"},
+		{0.5, 0.25, 3, "[0.25, 0.5]"},
+		{-0.1, 0.1, 2, "[-0.11, 0.11]"},
+		{math.Inf(-1), 1.0 / 3, 4, "(-Infinity, 0.3334]"},
+		{1e300, math.Inf(1), 1, "[1E+300, Infinity)"},
+		{123456, 123456, 3, "[1.23E+5, 1.24E+5]"},
+		{1234.5, 1234.5, 10, "[1234.5, 1234.5]"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.result, func(t *testing.T) {
+			s, err := FromFloat64Interval(c.lo, c.hi, c.precision)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := s.Validate(); err != nil {
+				t.Fatal(err)
+			}
+			r := s.String()
+			if r != c.result {
+				t.Fatalf("Expected '%v', but got '%v'", c.result, r)
+			}
+
+			// The float bounds of the enclosure enclose the original floats.
+			bounds := s.ToFloat64Bounds()
+			if len(bounds) != 1 || bounds[0][0] > math.Min(c.lo, c.hi) || bounds[0][1] < math.Max(c.lo, c.hi) {
+				t.Fatalf("Expected %v to enclose [%v, %v]", bounds, c.lo, c.hi)
+			}
+		})
+	}
+
+	if _, err := FromFloat64Interval(math.NaN(), 1, 3); err == nil {
+		t.Fatalf("Expected NaN to be rejected")
+	}
+}
+
+func TestFromBigFloat(t *testing.T) {
+	x := new(big.Float).SetPrec(200).Quo(big.NewFloat(1), big.NewFloat(3))
+	s, err := FromBigFloat(x, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r := s.String(); r != "[0.33333, 0.33334]" {
+		t.Fatalf("Unexpected enclosure '%v'", r)
+	}
+
+	s, err = FromBigFloat(big.NewFloat(0.375), 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r := s.String(); r != "[0.375, 0.375]" {
+		t.Fatalf("Unexpected enclosure '%v'", r)
+	}
+
+	if _, err := FromBigFloat(new(big.Float).SetInf(false), 5); err == nil {
+		t.Fatalf("Expected infinity to be rejected")
+	}
+}
+
+func TestToFloat64Bounds(t *testing.T) {
+	type testcase struct {
+		b      []boundDef
+		lo, hi float64
+	}
+
+	cases := []testcase{
+		{[]boundDef{{"0.1", false}, {"0.1", false}}, math.Nextafter(0.1, 0), 0.1},
+		{[]boundDef{{"0.5", false}, {"0.75", true}}, 0.5, 0.75},
+		{[]boundDef{{"-0.1", false}, {"0.3", false}}, -0.1, math.Nextafter(0.3, 1)},
+		{[]boundDef{{"-infinity", true}, {"1E+400", false}}, math.Inf(-1), math.Inf(1)},
+		{[]boundDef{{"1E+400", false}, {"infinity", true}}, math.MaxFloat64, math.Inf(1)},
+		{[]boundDef{{"1E-400", false}, {"1E-400", false}}, 0, math.SmallestNonzeroFloat64},
+	}
+
+	for _, c := range cases {
+		s := newFromBounds(c.b[0], c.b[1])
+		bounds := s.ToFloat64Bounds()
+		if len(bounds) != 1 || bounds[0][0] != c.lo || bounds[0][1] != c.hi {
+			t.Fatalf("Expected bounds of '%v' to be [%v, %v], but got %v", s.String(), c.lo, c.hi, bounds)
+		}
+
+		// Both bounds are as tight as possible.
+		l := ratFromDecimal(s.Intervals()[0].Lower)
+		next := math.Nextafter(c.lo, math.Inf(1))
+		if !math.IsInf(c.lo, 0) && !math.IsInf(next, 0) && new(big.Rat).SetFloat64(next).Cmp(l) <= 0 && new(big.Rat).SetFloat64(c.lo).Cmp(l) != 0 {
+			t.Fatalf("Lower bound %v of '%v' is not tight", c.lo, s.String())
+		}
+	}
+
+	d, _, _ := apd.BaseContext.NewFromString("2")
+	s := New(*d, false, *d, false).Complement()
+	bounds := s.ToFloat64Bounds()
+	if len(bounds) != 2 || bounds[0] != [2]float64{math.Inf(-1), 2} || bounds[1] != [2]float64{2, math.Inf(1)} {
+		t.Fatalf("Unexpected bounds %v", bounds)
+	}
+}
@@ -0,0 +1,97 @@
+package apis
+
+// The functions in this file reuse the capacity of an existing Set's items
+// rather than allocating, for hot paths. Sets are values sharing their items,
+// so any copy of a Set made before it is reused must not be used afterwards;
+// Clone the set first to keep the original.
+
+// UnionInto sets dst to the union of a and b, reusing dst's capacity. dst must
+// not share items with a or b; use UnionWith to update a set in place.
+func UnionInto(dst *Set, a, b Set) {
+	*dst = combineSets(dst.items, a, b, opUnion)
+}
+
+// IntersectionInto sets dst to the intersection of a and b, as UnionInto does.
+func IntersectionInto(dst *Set, a, b Set) {
+	*dst = combineSets(dst.items, a, b, opIntersection)
+}
+
+// DifferenceInto sets dst to a less b, as UnionInto does.
+func DifferenceInto(dst *Set, a, b Set) {
+	*dst = combineSets(dst.items, a, b, opDifference)
+}
+
+// SymmetricDifferenceInto sets dst to the numbers in exactly one of a and b,
+// as UnionInto does.
+func SymmetricDifferenceInto(dst *Set, a, b Set) {
+	*dst = combineSets(dst.items, a, b, opSymmetricDifference)
+}
+
+// UnionWith sets s to its union with b. It only allocates if s lacks the
+// capacity for the items of both.
+func (s *Set) UnionWith(b Set) {
+	s.combineWith(b, opUnion)
+}
+
+// IntersectWith sets s to its intersection with b, as UnionWith does.
+func (s *Set) IntersectWith(b Set) {
+	s.combineWith(b, opIntersection)
+}
+
+// combineWith combines s with b in place. It moves s's items to the end of
+// a buffer with room for b's before them, so that the sweep, which emits at
+// most one item for each it reads, never overwrites an item it has yet to
+// read.
+func (s *Set) combineWith(b Set, op func(inA, inB bool) bool) {
+	if sharesItems(s.items, b.items) {
+		b = Set{items: append([]item(nil), b.items...)}
+	}
+	n, m := len(s.items), len(b.items)
+	buf := s.items
+	if cap(buf) < n+m {
+		buf = make([]item, n+m)
+		copy(buf[m:], s.items)
+	} else {
+		buf = buf[:n+m]
+		copy(buf[m:], buf[:n])
+	}
+	*s = combineSets(buf[:0], Set{items: buf[m:]}, b, op)
+}
+
+// sharesItems reports whether a and b are slices of the same array.
+func sharesItems(a, b []item) bool {
+	return cap(a) > 0 && cap(b) > 0 && &a[:cap(a)][cap(a)-1] == &b[:cap(b)][cap(b)-1]
+}
+
+// ComplementInPlace sets s to its complement. It only allocates if s lacks
+// the capacity for two more items.
+func (s *Set) ComplementInPlace() {
+	items := s.items
+	for i, v := range items {
+		switch v.b {
+		case lower:
+			items[i] = item{upper, v.d, !v.open}
+		case upper:
+			items[i] = item{lower, v.d, !v.open}
+		}
+	}
+
+	// Numbers below the first item are now in-set, which needs a lower bound
+	// at negative infinity, unless the set was unbounded below.
+	if n := len(items); n > 0 && items[0].b == upper && isNegativeInfinity(items[0].d) {
+		copy(items, items[1:])
+		items = items[:n-1]
+	} else {
+		items = append(items, item{})
+		copy(items[1:], items)
+		items[0] = item{lower, negativeInfinity, true}
+	}
+
+	// Likewise above the last item.
+	if n := len(items); items[n-1].b == lower && isPositiveInfinity(items[n-1].d) {
+		items = items[:n-1]
+	} else {
+		items = append(items, item{upper, positiveInfinity, true})
+	}
+	s.items = items
+}
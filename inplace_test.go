@@ -0,0 +1,171 @@
+package apis
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/cockroachdb/apd/v3"
+)
+
+func TestInPlaceMatchesOperations(t *testing.T) {
+	sets := randomSets(200, 2)
+	for i := range sets {
+		if i%3 == 0 {
+			sets[i] = sets[i].Complement()
+		}
+	}
+
+	for i := 0; i+3 < len(sets); i += 2 {
+		a := sets[i].Union(sets[i+2])
+		b := sets[i+1].Union(sets[i+3])
+
+		type testcase struct {
+			name     string
+			expected Set
+			actual   func() Set
+		}
+
+		cases := []testcase{
+			{"UnionInto", a.Union(b), func() Set { var s Set; UnionInto(&s, a, b); return s }},
+			{"IntersectionInto", a.Intersection(b), func() Set { var s Set; IntersectionInto(&s, a, b); return s }},
+			{"DifferenceInto", a.Difference(b), func() Set { var s Set; DifferenceInto(&s, a, b); return s }},
+			{"SymmetricDifferenceInto", a.SymmetricDifference(b), func() Set { var s Set; SymmetricDifferenceInto(&s, a, b); return s }},
+			{"UnionWith", a.Union(b), func() Set { s := a.Union(Set{}); s.UnionWith(b); return s }},
+			{"IntersectWith", a.Intersection(b), func() Set { s := a.Union(Set{}); s.IntersectWith(b); return s }},
+			{"UnionWith itself", a, func() Set { s := a.Union(Set{}); s.UnionWith(s); return s }},
+			{"IntersectWith itself", a, func() Set { s := a.Union(Set{}); s.IntersectWith(s); return s }},
+			{"ComplementInPlace", a.Complement(), func() Set { s := a.Union(Set{}); s.ComplementInPlace(); return s }},
+		}
+
+		for _, c := range cases {
+			s := c.actual()
+			if err := s.Validate(); err != nil {
+				t.Fatalf("%v: %v", c.name, err)
+			}
+			if !s.Equal(c.expected) {
+				t.Fatalf("%v: Expected '%v', but got '%v'", c.name, c.expected.String(), s.String())
+			}
+		}
+	}
+
+	for _, b := range []boundDef{{"-infinity", true}, {"0", false}} {
+		s, expected := newFromBounds(b, boundDef{"infinity", true}), newFromBounds(b, boundDef{"infinity", true})
+		s.ComplementInPlace()
+		s.ComplementInPlace()
+		if r := s.String(); r != expected.String() {
+			t.Fatalf("Expected double complement to be unchanged, but got '%v'", r)
+		}
+	}
+}
+
+// TestInPlaceWithPointsMatchesOperations combines sets holding runs of
+// single points and exclusions, whose 'both' items the sweep copies or skips
+// as whole runs while reading and writing the same buffer.
+func TestInPlaceWithPointsMatchesOperations(t *testing.T) {
+	r := rand.New(rand.NewSource(4))
+	random := func() Set {
+		s := Set{}
+		for i := r.Intn(4); i >= 0; i-- {
+			l := r.Int63n(40)
+			s = s.Union(New(*apd.New(l, 0), r.Intn(2) == 0, *apd.New(l+r.Int63n(10), 0), r.Intn(2) == 0))
+		}
+		for i := r.Intn(6); i >= 0; i-- {
+			d := *apd.New(r.Int63n(40), 0)
+			if r.Intn(2) == 0 {
+				s = s.Union(New(d, false, d, false))
+			} else {
+				s = s.Difference(New(d, false, d, false))
+			}
+		}
+		if r.Intn(2) == 0 {
+			s = s.Complement()
+		}
+		return s
+	}
+
+	for n := 0; n < 5000; n++ {
+		a, b := random(), random()
+		for _, c := range []struct {
+			name     string
+			expected Set
+			with     func(s *Set, b Set)
+		}{
+			{"UnionWith", a.Union(b), (*Set).UnionWith},
+			{"IntersectWith", a.Intersection(b), (*Set).IntersectWith},
+		} {
+			s := a.Clone()
+			c.with(&s, b)
+			if err := s.Validate(); err != nil {
+				t.Fatalf("%v of '%v' and '%v': %v", c.name, a.String(), b.String(), err)
+			}
+			if !s.Equal(c.expected) {
+				t.Fatalf("%v of '%v' and '%v': Expected '%v', but got '%v'", c.name, a.String(), b.String(), c.expected.String(), s.String())
+			}
+		}
+	}
+}
+
+func TestInPlaceDoesNotAllocate(t *testing.T) {
+	sets := randomSets(100, 3)
+	a, b := UnionAll(sets[:50]...), UnionAll(sets[50:]...)
+	c := a.Union(b)
+
+	dst := Set{items: make([]item, 0, len(a.items)+len(b.items))}
+	s := Set{items: make([]item, 0, len(a.items)+len(b.items)+2)}
+
+	type testcase struct {
+		name string
+		fn   func()
+	}
+
+	cases := []testcase{
+		{"UnionInto", func() { UnionInto(&dst, a, b) }},
+		{"IntersectionInto", func() { IntersectionInto(&dst, a, b) }},
+		{"UnionWith", func() { s.items = append(s.items[:0], a.items...); s.UnionWith(b) }},
+		{"IntersectWith", func() { s.items = append(s.items[:0], a.items...); s.IntersectWith(b) }},
+		{"ComplementInPlace", func() { s.items = append(s.items[:0], c.items...); s.ComplementInPlace() }},
+	}
+
+	for _, tc := range cases {
+		if allocs := testing.AllocsPerRun(10, tc.fn); allocs != 0 {
+			t.Fatalf("Expected %v not to allocate, but got %v allocations", tc.name, allocs)
+		}
+	}
+}
+
+func BenchmarkOperations(b *testing.B) {
+	for _, n := range []int{10, 1000} {
+		sets := randomSets(2*n, 4)
+		x, y := UnionAll(sets[:n]...), UnionAll(sets[n:]...)
+		dst := Set{}
+		s := Set{}
+
+		type benchmark struct {
+			name string
+			fn   func()
+		}
+
+		benchmarks := []benchmark{
+			{"Union", func() { x.Union(y) }},
+			{"UnionInto", func() { UnionInto(&dst, x, y) }},
+			{"UnionWith", func() { s.items = append(s.items[:0], x.items...); s.UnionWith(y) }},
+			{"Intersection", func() { x.Intersection(y) }},
+			{"IntersectionInto", func() { IntersectionInto(&dst, x, y) }},
+			{"IntersectWith", func() { s.items = append(s.items[:0], x.items...); s.IntersectWith(y) }},
+			{"Difference", func() { x.Difference(y) }},
+			{"DifferenceInto", func() { DifferenceInto(&dst, x, y) }},
+			{"Complement", func() { x.Complement() }},
+			{"ComplementInPlace", func() { s.items = append(s.items[:0], x.items...); s.ComplementInPlace() }},
+		}
+
+		for _, bm := range benchmarks {
+			b.Run(fmt.Sprintf("%v/%v", bm.name, n), func(b *testing.B) {
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					bm.fn()
+				}
+			})
+		}
+	}
+}
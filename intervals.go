@@ -0,0 +1,143 @@
+package apis
+
+import (
+	"sort"
+
+	"github.com/cockroachdb/apd/v3"
+)
+
+// Interval is a single connected range of decimals. Infinite bounds are
+// always treated as open.
+type Interval struct {
+	Lower     apd.Decimal
+	LowerOpen bool
+	Upper     apd.Decimal
+	UpperOpen bool
+}
+
+// IsEmpty reports whether the interval contains no numbers.
+func (iv Interval) IsEmpty() bool {
+	c := iv.Lower.Cmp(&iv.Upper)
+	return c > 0 || c == 0 && (iv.LowerOpen || iv.UpperOpen || iv.Lower.Form == apd.Infinite)
+}
+
+// normalize opens infinite bounds, as New does.
+func (iv Interval) normalize() Interval {
+	if iv.Lower.Form == apd.Infinite {
+		iv.LowerOpen = true
+	}
+	if iv.Upper.Form == apd.Infinite {
+		iv.UpperOpen = true
+	}
+	return iv
+}
+
+// Intervals decomposes the set into its maximal connected intervals, in
+// increasing order. An excluded point splits the interval around it in two.
+func (s Set) Intervals() []Interval {
+	res := []Interval{}
+	var cur Interval
+	in := false
+	for _, v := range s.items {
+		switch v.b {
+		case lower:
+			cur = Interval{Lower: v.d, LowerOpen: v.open}
+			in = true
+		case upper:
+			cur.Upper, cur.UpperOpen = v.d, v.open
+			res = append(res, cur)
+			in = false
+		case both:
+			if in {
+				cur.Upper, cur.UpperOpen = v.d, true
+				res = append(res, cur)
+				cur = Interval{Lower: v.d, LowerOpen: true}
+			} else {
+				res = append(res, Interval{Lower: v.d, Upper: v.d})
+			}
+		}
+	}
+	for i := range res {
+		res[i].Lower, res[i].Upper = cloneDecimal(res[i].Lower), cloneDecimal(res[i].Upper)
+	}
+	return res
+}
+
+// FromIntervals returns the union of the given intervals, which may be in any
+// order and may overlap.
+func FromIntervals(ivs ...Interval) Set {
+	sorted := make([]Interval, 0, len(ivs))
+	for _, iv := range ivs {
+		if !iv.IsEmpty() {
+			sorted = append(sorted, iv.normalize())
+		}
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		c := sorted[i].Lower.Cmp(&sorted[j].Lower)
+		return c < 0 || c == 0 && !sorted[i].LowerOpen && sorted[j].LowerOpen
+	})
+
+	merged := []Interval{}
+	for _, iv := range sorted {
+		merged = appendInterval(merged, iv)
+	}
+	return Set{items: itemsFromIntervals(merged)}.own()
+}
+
+// appendInterval adds iv to the end of a sorted list of disjoint intervals,
+// merging it into the last interval if they overlap or touch. iv must not
+// start before the last interval does.
+func appendInterval(ivs []Interval, iv Interval) []Interval {
+	n := len(ivs)
+	if n == 0 {
+		return append(ivs, iv)
+	}
+	last := &ivs[n-1]
+	c := iv.Lower.Cmp(&last.Upper)
+	if c > 0 || c == 0 && iv.LowerOpen && last.UpperOpen {
+		return append(ivs, iv)
+	}
+	switch iv.Upper.Cmp(&last.Upper) {
+	case 1:
+		last.Upper, last.UpperOpen = iv.Upper, iv.UpperOpen
+	case 0:
+		last.UpperOpen = last.UpperOpen && iv.UpperOpen
+	}
+	return ivs
+}
+
+// itemsFromIntervals encodes a sorted list of disjoint, non-empty intervals.
+// Intervals may only meet at a point excluded from both.
+func itemsFromIntervals(ivs []Interval) []item {
+	items := make([]item, 0, 2*len(ivs))
+	for i, iv := range ivs {
+		if iv.Lower.Cmp(&iv.Upper) == 0 {
+			items = append(items, item{both, iv.Lower, false})
+			continue
+		}
+		if i > 0 && ivs[i-1].Upper.Cmp(&iv.Lower) == 0 {
+			// Replace the previous upper bound with an exclusion.
+			items[len(items)-1].b = both
+			items[len(items)-1].open = false
+		} else {
+			items = append(items, item{lower, iv.Lower, iv.LowerOpen})
+		}
+		items = append(items, item{upper, iv.Upper, iv.UpperOpen})
+	}
+	return items
+}
+
+// Measure returns the total length of the set's intervals, which is Infinity
+// if the set is unbounded. Isolated points have no length.
+func (s Set) Measure() apd.Decimal {
+	var total apd.Decimal
+	for _, iv := range s.Intervals() {
+		if iv.Lower.Form == apd.Infinite || iv.Upper.Form == apd.Infinite {
+			return positiveInfinity
+		}
+		var length apd.Decimal
+		_, _ = apd.BaseContext.Sub(&length, &iv.Upper, &iv.Lower)
+		_, _ = apd.BaseContext.Add(&total, &total, &length)
+	}
+	return total
+}
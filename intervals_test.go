@@ -0,0 +1,99 @@
+package apis
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/apd/v3"
+)
+
+func newInterval(b1 boundDef, b2 boundDef) Interval {
+	l, _, _ := apd.BaseContext.NewFromString(b1.s)
+	u, _, _ := apd.BaseContext.NewFromString(b2.s)
+	return Interval{Lower: *l, LowerOpen: b1.o, Upper: *u, UpperOpen: b2.o}
+}
+
+func TestFromIntervals(t *testing.T) {
+	type testcase struct {
+		b      []boundDef
+		result string
+	}
+
+	cases := []testcase{
+		{[]boundDef{{"2", false}, {"3", true}, {"0", false}, {"1", false}}, "[0, 1], [2, 3)"},
+		{[]boundDef{{"0", false}, {"1", true}, {"1", true}, {"2", false}}, "[0, 1), (1, 2]"},
+		{[]boundDef{{"0", false}, {"1", false}, {"1", true}, {"2", false}}, "[0, 2]"},
+		{[]boundDef{{"0", false}, {"5", true}, {"1", true}, {"2", false}}, "[0, 5)"},
+		{[]boundDef{{"3", false}, {"3", false}, {"1", true}, {"1", false}}, "[3, 3]"},
+		{[]boundDef{{"-infinity", false}, {"0", false}, {"0", true}, {"infinity", false}}, "(-Infinity, Infinity)"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.result, func(t *testing.T) {
+			s := FromIntervals(newInterval(c.b[0], c.b[1]), newInterval(c.b[2], c.b[3]))
+			if err := s.Validate(); err != nil {
+				t.Fatal(err)
+			}
+			r := s.String()
+			if r != c.result {
+				t.Fatalf("Expected '%v', but got '%v'", c.result, r)
+			}
+
+			// Decomposing and rebuilding is the identity.
+			rebuilt := FromIntervals(s.Intervals()...)
+			if rebuilt.String() != r {
+				t.Fatalf("Expected '%v' to round trip, but got '%v'", r, rebuilt.String())
+			}
+		})
+	}
+}
+
+func TestContains(t *testing.T) {
+	s := FromIntervals(
+		newInterval(boundDef{"0", true}, boundDef{"1", true}),
+		newInterval(boundDef{"1", true}, boundDef{"2", false}),
+		newInterval(boundDef{"3", false}, boundDef{"3", false}),
+	)
+
+	type membership struct {
+		d  string
+		in bool
+	}
+	for _, m := range []membership{
+		{"-1", false},
+		{"0", false},
+		{"0.5", true},
+		{"1", false},
+		{"1.00", false},
+		{"2", true},
+		{"2.5", false},
+		{"3", true},
+		{"4", false},
+	} {
+		d, _, _ := apd.BaseContext.NewFromString(m.d)
+		if s.Contains(*d) != m.in {
+			t.Fatalf("Expected membership of %v in '%v' to be %v", m.d, s.String(), m.in)
+		}
+	}
+}
+
+func TestMeasure(t *testing.T) {
+	type testcase struct {
+		s       Set
+		measure string
+	}
+
+	cases := []testcase{
+		{Set{}, "0"},
+		{newFromBounds(boundDef{"0.5", true}, boundDef{"2", false}), "1.5"},
+		{newFromBounds(boundDef{"3", false}, boundDef{"3", false}), "0"},
+		{newFromBounds(boundDef{"0", false}, boundDef{"1", false}).Union(newFromBounds(boundDef{"2", false}, boundDef{"2.25", true})), "1.25"},
+		{newFromBounds(boundDef{"0", false}, boundDef{"0", false}).Complement(), "Infinity"},
+	}
+
+	for _, c := range cases {
+		m := c.s.Measure()
+		if m.String() != c.measure {
+			t.Fatalf("Expected measure of '%v' to be %v, but got %v", c.s.String(), c.measure, m.String())
+		}
+	}
+}
@@ -0,0 +1,187 @@
+package apis
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/cockroachdb/apd/v3"
+)
+
+// IntSet is a set of integers. It shares Set's representation, but is kept
+// in a discrete canonical form: every finite bound is a closed integer, and
+// intervals with no integers between them, like [1, 3] and [4, 5], are
+// coalesced into one.
+type IntSet struct {
+	s Set
+}
+
+// NewInt64 returns the integers from l to u inclusive.
+func NewInt64(l int64, u int64) IntSet {
+	return NewBigInt(big.NewInt(l), big.NewInt(u))
+}
+
+// NewBigInt returns the integers from l to u inclusive. A nil bound is
+// unbounded.
+func NewBigInt(l *big.Int, u *big.Int) IntSet {
+	ld, ud := negativeInfinity, positiveInfinity
+	if l != nil {
+		ld = decimalFromBigInt(l)
+	}
+	if u != nil {
+		ud = decimalFromBigInt(u)
+	}
+	return Discrete(New(ld, false, ud, false))
+}
+
+// Discrete returns the integers contained in s.
+func Discrete(s Set) IntSet {
+	ivs := []Interval{}
+	for _, iv := range s.Intervals() {
+		l, u := iv.Lower, iv.Upper
+		if l.Form != apd.Infinite {
+			b := ceilBigInt(l)
+			if iv.LowerOpen {
+				b = floorBigInt(l)
+				b.Add(b, big.NewInt(1))
+			}
+			l = decimalFromBigInt(b)
+		}
+		if u.Form != apd.Infinite {
+			b := floorBigInt(u)
+			if iv.UpperOpen {
+				b = ceilBigInt(u)
+				b.Sub(b, big.NewInt(1))
+			}
+			u = decimalFromBigInt(b)
+		}
+
+		next := Interval{Lower: l, Upper: u}.normalize()
+		if next.IsEmpty() {
+			continue
+		}
+		if n := len(ivs); n > 0 && adjacent(ivs[n-1].Upper, next.Lower) {
+			ivs[n-1].Upper, ivs[n-1].UpperOpen = next.Upper, next.UpperOpen
+			continue
+		}
+		ivs = append(ivs, next)
+	}
+	return IntSet{Set{items: itemsFromIntervals(ivs)}}
+}
+
+// adjacent reports whether the integer l is directly followed by u.
+func adjacent(l apd.Decimal, u apd.Decimal) bool {
+	var next apd.Decimal
+	_, _ = apd.BaseContext.Add(&next, &l, decimalOne)
+	return next.Cmp(&u) == 0
+}
+
+var decimalOne = apd.New(1, 0)
+
+func decimalFromBigInt(b *big.Int) apd.Decimal {
+	var d apd.Decimal
+	d.Coeff.SetMathBigInt(b)
+	if d.Coeff.Sign() < 0 {
+		d.Coeff.Neg(&d.Coeff)
+		d.Negative = true
+	}
+	return d
+}
+
+// bigIntFromDecimal truncates a finite decimal towards zero.
+func bigIntFromDecimal(d apd.Decimal) *big.Int {
+	var integ, frac apd.Decimal
+	d.Modf(&integ, &frac)
+	b := integ.Coeff.MathBigInt()
+	if integ.Exponent > 0 {
+		b.Mul(b, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(integ.Exponent)), nil))
+	}
+	if integ.Negative {
+		b.Neg(b)
+	}
+	return b
+}
+
+func floorBigInt(d apd.Decimal) *big.Int {
+	var f apd.Decimal
+	_, _ = apd.BaseContext.Floor(&f, &d)
+	return bigIntFromDecimal(f)
+}
+
+func ceilBigInt(d apd.Decimal) *big.Int {
+	var c apd.Decimal
+	_, _ = apd.BaseContext.Ceil(&c, &d)
+	return bigIntFromDecimal(c)
+}
+
+// Set returns the decimals that are members of a, for use with Set
+// operations.
+func (a IntSet) Set() Set {
+	return a.s.Clone()
+}
+
+func (a IntSet) String() string {
+	return a.s.String()
+}
+
+func (a IntSet) Union(b IntSet) IntSet {
+	return Discrete(a.s.Union(b.s))
+}
+
+func (a IntSet) Intersection(b IntSet) IntSet {
+	return Discrete(a.s.Intersection(b.s))
+}
+
+// Complement returns the integers not in a, as closed intervals.
+func (a IntSet) Complement() IntSet {
+	return Discrete(a.s.Complement())
+}
+
+func (a IntSet) IsEmpty() bool {
+	return a.s.IsEmpty()
+}
+
+func (a IntSet) Contains(x *big.Int) bool {
+	return a.s.Contains(decimalFromBigInt(x))
+}
+
+func (a IntSet) ContainsInt64(x int64) bool {
+	return a.Contains(big.NewInt(x))
+}
+
+// Count returns the number of integers in a, or false if a is infinite.
+func (a IntSet) Count() (*big.Int, bool) {
+	n := new(big.Int)
+	for _, iv := range a.s.Intervals() {
+		if iv.Lower.Form == apd.Infinite || iv.Upper.Form == apd.Infinite {
+			return nil, false
+		}
+		n.Add(n, bigIntFromDecimal(iv.Upper))
+		n.Sub(n, bigIntFromDecimal(iv.Lower))
+		n.Add(n, big.NewInt(1))
+	}
+	return n, true
+}
+
+// Each calls fn with each member of a in increasing order, until fn returns
+// false. Sets that are unbounded above are enumerated until fn stops it;
+// sets that are unbounded below cannot be enumerated in order, and return an
+// error.
+func (a IntSet) Each(fn func(x *big.Int) bool) error {
+	ivs := a.s.Intervals()
+	if len(ivs) > 0 && ivs[0].Lower.Form == apd.Infinite {
+		return fmt.Errorf("%v is unbounded below, so cannot be enumerated", a.String())
+	}
+	one := big.NewInt(1)
+	for _, iv := range ivs {
+		var u *big.Int
+		if iv.Upper.Form != apd.Infinite {
+			u = bigIntFromDecimal(iv.Upper)
+		}
+		for x := bigIntFromDecimal(iv.Lower); u == nil || x.Cmp(u) <= 0; x.Add(x, one) {
+			if !fn(new(big.Int).Set(x)) {
+				return nil
+			}
+		}
+	}
+	return nil
+}
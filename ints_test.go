@@ -0,0 +1,106 @@
+package apis
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestDiscrete(t *testing.T) {
+	type testcase struct {
+		b                []boundDef
+		result           string
+		complementResult string
+	}
+
+	cases := []testcase{
+		{[]boundDef{{"1", false}, {"3", false}, {"4", false}, {"5", false}}, "[1, 5]", "(-Infinity, 0], [6, Infinity)"},
+		{[]boundDef{{"1", true}, {"4", true}, {"2", false}, {"3", false}}, "[2, 3]", "(-Infinity, 1], [4, Infinity)"},
+		{[]boundDef{{"0.5", false}, {"2.5", false}, {"2.7", false}, {"3.2", false}}, "[1, 3]", "(-Infinity, 0], [4, Infinity)"},
+		{[]boundDef{{"1.1", false}, {"1.9", false}, {"5", true}, {"5", true}}, "[5, 5]", "(-Infinity, 4], [6, Infinity)"},
+		{[]boundDef{{"-infinity", true}, {"-1", true}, {"1", true}, {"infinity", true}}, "(-Infinity, -2], [2, Infinity)", "[-1, 1]"},
+		{[]boundDef{{"-infinity", true}, {"0", true}, {"0", false}, {"infinity", true}}, "(-Infinity, Infinity)", ""},
+		{[]boundDef{{"0.1", false}, {"0.9", false}, {"2.1", false}, {"2.9", false}}, "", "(-Infinity, Infinity)"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.result, func(t *testing.T) {
+			s := newFromBounds(c.b[0], c.b[1]).Union(newFromBounds(c.b[2], c.b[3]))
+			n := Discrete(s)
+			if err := n.s.Validate(); err != nil {
+				t.Fatal(err)
+			}
+			r := n.String()
+			if r != c.result {
+				t.Fatalf("Expected '%v', but got '%v'", c.result, r)
+			}
+
+			comp := n.Complement()
+			if err := comp.s.Validate(); err != nil {
+				t.Fatal(err)
+			}
+			r = comp.String()
+			if r != c.complementResult {
+				t.Fatalf("Expected complement '%v', but got '%v'", c.complementResult, r)
+			}
+		})
+	}
+}
+
+func TestIntSetOperations(t *testing.T) {
+	a := NewInt64(1, 3).Union(NewInt64(4, 5)).Union(NewInt64(10, 12))
+	if r := a.String(); r != "[1, 5], [10, 12]" {
+		t.Fatalf("Unexpected union '%v'", r)
+	}
+
+	b := NewInt64(5, 10)
+	if r := a.Intersection(b).String(); r != "[5, 5], [10, 10]" {
+		t.Fatalf("Unexpected intersection '%v'", r)
+	}
+
+	n, ok := a.Count()
+	if !ok || n.Int64() != 8 {
+		t.Fatalf("Expected a count of 8, but got %v", n)
+	}
+	if _, ok := a.Complement().Count(); ok {
+		t.Fatalf("Expected the complement to be infinite")
+	}
+
+	members := []int64{}
+	err := a.Each(func(x *big.Int) bool {
+		members = append(members, x.Int64())
+		return true
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []int64{1, 2, 3, 4, 5, 10, 11, 12}
+	if len(members) != len(expected) {
+		t.Fatalf("Expected members %v, but got %v", expected, members)
+	}
+	for i := range expected {
+		if members[i] != expected[i] {
+			t.Fatalf("Expected members %v, but got %v", expected, members)
+		}
+	}
+
+	if !a.ContainsInt64(4) || a.ContainsInt64(6) || a.ContainsInt64(0) {
+		t.Fatalf("Unexpected membership in '%v'", a.String())
+	}
+
+	huge, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+	h := NewBigInt(huge, nil)
+	if !h.Contains(huge) || h.Contains(new(big.Int).Sub(huge, big.NewInt(1))) {
+		t.Fatalf("Unexpected membership in '%v'", h.String())
+	}
+	count := 0
+	err = h.Each(func(x *big.Int) bool {
+		count++
+		return count < 3
+	})
+	if err != nil || count != 3 {
+		t.Fatalf("Expected to stop enumeration after 3 members, got %v, %v", count, err)
+	}
+	if err := h.Complement().Each(func(*big.Int) bool { return true }); err == nil {
+		t.Fatalf("Expected enumeration of a set unbounded below to fail")
+	}
+}
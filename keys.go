@@ -0,0 +1,114 @@
+package apis
+
+import (
+	"bytes"
+	"strconv"
+)
+
+// Span is a half-open range of keys [Start, End), in lexicographic order. A
+// nil End is unbounded.
+type Span struct {
+	Start, End []byte
+}
+
+// PrefixEnd returns the first key after every key starting with prefix, or
+// nil if there is no such key because the prefix is empty or all 0xff.
+func PrefixEnd(prefix []byte) []byte {
+	for i := len(prefix) - 1; i >= 0; i-- {
+		if prefix[i] != 0xff {
+			end := append([]byte(nil), prefix[:i+1]...)
+			end[i]++
+			return end
+		}
+	}
+	return nil
+}
+
+// KeySet is a set of byte string keys, such as the key spans of a sorted
+// key-value store.
+//
+// As the immediate successor of every key k is k followed by a zero byte,
+// any set of keys can be described by half-open spans, so a KeySet always
+// holds closed lower bounds and open upper bounds. Every operation preserves
+// that form, so it has no exclusions or isolated points either.
+type KeySet struct {
+	r ranges[[]byte]
+}
+
+// NewKeySpan returns the keys in [start, end). A nil end is unbounded.
+func NewKeySpan(start []byte, end []byte) KeySet {
+	start = append([]byte{}, start...)
+	if end == nil {
+		return KeySet{atLeast(start, false)}
+	}
+	if bytes.Compare(start, end) >= 0 {
+		return KeySet{}
+	}
+	end = append([]byte{}, end...)
+	return KeySet{ranges[[]byte]{items: []endpoint[[]byte]{{lower, start, false}, {upper, end, true}}}}
+}
+
+// NewKey returns the set holding only k.
+func NewKey(k []byte) KeySet {
+	return NewKeySpan(k, append(append([]byte{}, k...), 0))
+}
+
+// NewKeyPrefix returns every key starting with prefix.
+func NewKeyPrefix(prefix []byte) KeySet {
+	return NewKeySpan(prefix, PrefixEnd(prefix))
+}
+
+// AllKeys returns every key.
+func AllKeys() KeySet {
+	return NewKeySpan(nil, nil)
+}
+
+func (a KeySet) Union(b KeySet) KeySet {
+	return KeySet{a.r.union(b.r, bytes.Compare)}
+}
+
+func (a KeySet) Intersection(b KeySet) KeySet {
+	return KeySet{a.r.intersection(b.r, bytes.Compare)}
+}
+
+func (a KeySet) Difference(b KeySet) KeySet {
+	return KeySet{a.r.difference(b.r, bytes.Compare)}
+}
+
+// Complement returns every key not in a. As the empty key is the lowest key,
+// the complement is taken relative to AllKeys.
+func (a KeySet) Complement() KeySet {
+	return AllKeys().Difference(a)
+}
+
+func (a KeySet) Contains(k []byte) bool {
+	return a.r.contains(k, bytes.Compare)
+}
+
+func (a KeySet) IsEmpty() bool {
+	return a.r.isEmpty()
+}
+
+func (a KeySet) Validate() error {
+	return a.r.validate(bytes.Compare)
+}
+
+func (a KeySet) String() string {
+	return a.r.format(func(k []byte) string {
+		return strconv.Quote(string(k))
+	}, "-Infinity", "Infinity")
+}
+
+// Spans splits the set into its non-overlapping [start, end) spans, in
+// increasing order, suitable for range scans.
+func (a KeySet) Spans() []Span {
+	spans := []Span{}
+	for i := 0; i < len(a.r.items); i += 2 {
+		s := Span{Start: append([]byte{}, a.r.items[i].d...)}
+		if i+1 < len(a.r.items) {
+			s.End = append([]byte{}, a.r.items[i+1].d...)
+		}
+		spans = append(spans, s)
+	}
+	return spans
+}
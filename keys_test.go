@@ -0,0 +1,92 @@
+package apis
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestPrefixEnd(t *testing.T) {
+	type testcase struct {
+		prefix []byte
+		end    []byte
+	}
+
+	cases := []testcase{
+		{[]byte("a"), []byte("b")},
+		{[]byte("ab"), []byte("ac")},
+		{[]byte{'a', 0xff}, []byte("b")},
+		{[]byte{0xff, 0xff}, nil},
+		{[]byte{}, nil},
+	}
+
+	for _, c := range cases {
+		end := PrefixEnd(c.prefix)
+		if !bytes.Equal(end, c.end) || (end == nil) != (c.end == nil) {
+			t.Fatalf("Expected PrefixEnd(%q) to be %q, but got %q", c.prefix, c.end, end)
+		}
+	}
+}
+
+func TestKeySetOperations(t *testing.T) {
+	type testcase struct {
+		result string
+		s      KeySet
+	}
+
+	a := NewKeySpan([]byte("a"), []byte("m"))
+	b := NewKeyPrefix([]byte("k"))
+	cases := []testcase{
+		{`["a", "m")`, a},
+		{`["k", "l")`, b},
+		{`["a", Infinity)`, NewKeySpan([]byte("a"), nil)},
+		{`["", Infinity)`, AllKeys()},
+		{``, NewKeySpan([]byte("b"), []byte("a"))},
+		{`["k", "k\x00")`, NewKey([]byte("k"))},
+		{`["a", "m")`, a.Union(b)},
+		{`["a", "z")`, a.Union(NewKeySpan([]byte("m"), []byte("z")))},
+		{`["k", "l")`, a.Intersection(b)},
+		{`["a", "k"), ["l", "m")`, a.Difference(b)},
+		{`["", "a"), ["m", Infinity)`, a.Complement()},
+		{`["a", "m")`, a.Complement().Complement()},
+		{`["", "k"), ["k\x00", Infinity)`, NewKey([]byte("k")).Complement()},
+		{``, AllKeys().Complement()},
+	}
+
+	for _, c := range cases {
+		t.Run(c.result, func(t *testing.T) {
+			if err := c.s.Validate(); err != nil {
+				t.Fatal(err)
+			}
+			r := c.s.String()
+			if r != c.result {
+				t.Fatalf("Expected '%v', but got '%v'", c.result, r)
+			}
+		})
+	}
+
+	if !a.Contains([]byte("a")) || !a.Contains([]byte("lzzz")) || a.Contains([]byte("m")) || a.Contains([]byte("")) {
+		t.Fatalf("Unexpected membership in '%v'", a.String())
+	}
+}
+
+func TestKeySetSpans(t *testing.T) {
+	s := NewKeyPrefix([]byte("user/")).
+		Union(NewKey([]byte("config"))).
+		Union(NewKeySpan([]byte("z"), nil))
+
+	spans := s.Spans()
+	expected := []Span{
+		{[]byte("config"), []byte("config\x00")},
+		{[]byte("user/"), []byte("user0")},
+		{[]byte("z"), nil},
+	}
+	if len(spans) != len(expected) {
+		t.Fatalf("Expected %v spans, but got %v", len(expected), len(spans))
+	}
+	for i := range expected {
+		if !bytes.Equal(spans[i].Start, expected[i].Start) || !bytes.Equal(spans[i].End, expected[i].End) ||
+			(spans[i].End == nil) != (expected[i].End == nil) {
+			t.Fatalf("Expected span %q, but got %q", expected[i], spans[i])
+		}
+	}
+}
@@ -0,0 +1,193 @@
+package apis
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/cockroachdb/apd/v3"
+)
+
+// Lattice is the set of decimals offset + step*k, for integers k, that lie
+// within a Set. It describes discrete sets such as price ticks, like the
+// multiples of 0.05 in [0, 10], that the continuous representation of Set
+// cannot.
+//
+// All arithmetic on members is exact.
+type Lattice struct {
+	offset apd.Decimal
+	step   apd.Decimal
+	within Set
+}
+
+// NewLattice returns every offset + step*k, for any integer k. The sign of
+// step is irrelevant, but it must be finite and non-zero.
+func NewLattice(offset apd.Decimal, step apd.Decimal) (Lattice, error) {
+	if offset.Form != apd.Finite || step.Form != apd.Finite || step.IsZero() {
+		return Lattice{}, fmt.Errorf("lattice with offset %v and step %v is not well formed", &offset, &step)
+	}
+	step.Negative = false
+	return Lattice{
+		offset: offset,
+		step:   step,
+		within: New(negativeInfinity, true, positiveInfinity, true),
+	}, nil
+}
+
+func (l Lattice) String() string {
+	return fmt.Sprintf("{%v + %vk} in %v", &l.offset, &l.step, l.within.String())
+}
+
+// Intersection restricts the lattice to the members in s.
+func (l Lattice) Intersection(s Set) Lattice {
+	l.within = l.within.Intersection(s)
+	return l
+}
+
+// at returns offset + step*k.
+func (l Lattice) at(k *big.Int) apd.Decimal {
+	var d apd.Decimal
+	kd := decimalFromBigInt(k)
+	_, _ = apd.BaseContext.Mul(&d, &l.step, &kd)
+	_, _ = apd.BaseContext.Add(&d, &d, &l.offset)
+	return d
+}
+
+// index returns (d - offset) / step, the possibly fractional k at which d
+// lies.
+func (l Lattice) index(d apd.Decimal) *big.Rat {
+	r := ratFromDecimal(d)
+	r.Sub(r, ratFromDecimal(l.offset))
+	return r.Quo(r, ratFromDecimal(l.step))
+}
+
+// indices returns the integers k for which offset + step*k is a member.
+func (l Lattice) indices() IntSet {
+	ivs := []Interval{}
+	for _, iv := range l.within.Intervals() {
+		k := Interval{Lower: negativeInfinity, Upper: positiveInfinity}
+		if iv.Lower.Form != apd.Infinite {
+			k.Lower, k.LowerOpen = decimalFromBigInt(ratCeil(l.index(iv.Lower))), false
+			if iv.LowerOpen && l.onLattice(iv.Lower) {
+				k.LowerOpen = true
+			}
+		}
+		if iv.Upper.Form != apd.Infinite {
+			q, _ := floorRat(l.index(iv.Upper))
+			k.Upper = decimalFromBigInt(q)
+			if iv.UpperOpen && l.onLattice(iv.Upper) {
+				k.UpperOpen = true
+			}
+		}
+		ivs = append(ivs, k)
+	}
+	return Discrete(FromIntervals(ivs...))
+}
+
+func ratCeil(r *big.Rat) *big.Int {
+	q, m := floorRat(r)
+	if m.Sign() != 0 {
+		q.Add(q, big.NewInt(1))
+	}
+	return q
+}
+
+// onLattice reports whether d is offset + step*k for some integer k,
+// regardless of the restriction.
+func (l Lattice) onLattice(d apd.Decimal) bool {
+	return d.Form == apd.Finite && l.index(d).IsInt()
+}
+
+func (l Lattice) Contains(d apd.Decimal) bool {
+	return l.onLattice(d) && l.within.Contains(d)
+}
+
+func (l Lattice) IsEmpty() bool {
+	return l.indices().IsEmpty()
+}
+
+// Count returns the number of members, or false if there are infinitely many.
+func (l Lattice) Count() (*big.Int, bool) {
+	return l.indices().Count()
+}
+
+// Each calls fn with each member in increasing order, until fn returns false.
+// As with IntSet.Each, lattices unbounded below cannot be enumerated.
+func (l Lattice) Each(fn func(d apd.Decimal) bool) error {
+	return l.indices().Each(func(k *big.Int) bool {
+		return fn(l.at(k))
+	})
+}
+
+// Snap returns the member nearest to d, preferring the lower of two equally
+// near members. It returns false if the lattice is empty or d is NaN. An
+// infinite d snaps to the highest or lowest member, or returns false if the
+// lattice is unbounded in that direction.
+func (l Lattice) Snap(d apd.Decimal) (apd.Decimal, bool) {
+	switch d.Form {
+	case apd.NaN, apd.NaNSignaling:
+		return apd.Decimal{}, false
+	case apd.Infinite:
+		ivs := l.indices().Set().Intervals()
+		if len(ivs) == 0 {
+			return apd.Decimal{}, false
+		}
+		k := bigIntBound(ivs[0].Lower)
+		if !d.Negative {
+			k = bigIntBound(ivs[len(ivs)-1].Upper)
+		}
+		if k == nil {
+			return apd.Decimal{}, false
+		}
+		return l.at(k), true
+	}
+
+	x := l.index(d)
+
+	// Find the nearest member index on either side of x.
+	var below, above *big.Int
+	for _, iv := range l.indices().Set().Intervals() {
+		lo, hi := bigIntBound(iv.Lower), bigIntBound(iv.Upper)
+		if lo == nil || new(big.Rat).SetInt(lo).Cmp(x) <= 0 {
+			// The interval starts at or below x.
+			f, _ := floorRat(x)
+			if hi != nil && hi.Cmp(f) < 0 {
+				f = hi
+			}
+			below = f
+		}
+		if hi == nil || new(big.Rat).SetInt(hi).Cmp(x) >= 0 {
+			c := ratCeil(x)
+			if lo != nil && lo.Cmp(c) > 0 {
+				c = lo
+			}
+			above = c
+			break
+		}
+	}
+
+	switch {
+	case below == nil && above == nil:
+		return apd.Decimal{}, false
+	case below == nil:
+		return l.at(above), true
+	case above == nil:
+		return l.at(below), true
+	}
+
+	// Compare the distance to each, in units of step.
+	db := new(big.Rat).Sub(x, new(big.Rat).SetInt(below))
+	da := new(big.Rat).Sub(new(big.Rat).SetInt(above), x)
+	if da.Cmp(db) < 0 {
+		return l.at(above), true
+	}
+	return l.at(below), true
+}
+
+// bigIntBound returns the integer value of a finite bound, or nil for an
+// infinite one.
+func bigIntBound(d apd.Decimal) *big.Int {
+	if d.Form == apd.Infinite {
+		return nil
+	}
+	return bigIntFromDecimal(d)
+}
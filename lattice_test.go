@@ -0,0 +1,166 @@
+package apis
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/cockroachdb/apd/v3"
+)
+
+func newLattice(t *testing.T, offset string, step string) Lattice {
+	o, _, _ := apd.BaseContext.NewFromString(offset)
+	s, _, _ := apd.BaseContext.NewFromString(step)
+	l, err := NewLattice(*o, *s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return l
+}
+
+func decimal(s string) apd.Decimal {
+	d, _, _ := apd.BaseContext.NewFromString(s)
+	return *d
+}
+
+func TestLatticeMembers(t *testing.T) {
+	type testcase struct {
+		offset, step string
+		within       []boundDef
+		members      string
+	}
+
+	cases := []testcase{
+		{"0", "0.05", []boundDef{{"0", false}, {"0.2", false}}, "0.00, 0.05, 0.10, 0.15, 0.20"},
+		{"0", "0.05", []boundDef{{"0", true}, {"0.2", true}}, "0.05, 0.10, 0.15"},
+		{"0", "0.05", []boundDef{{"0.01", false}, {"0.12", false}}, "0.05, 0.10"},
+		{"0.01", "0.25", []boundDef{{"-0.5", false}, {"0.5", false}}, "-0.49, -0.24, 0.01, 0.26"},
+		{"3", "-2", []boundDef{{"0", false}, {"6", false}}, "1, 3, 5"},
+		{"0", "1", []boundDef{{"0.2", false}, {"0.8", false}}, ""},
+	}
+
+	for _, c := range cases {
+		t.Run(c.members, func(t *testing.T) {
+			l := newLattice(t, c.offset, c.step).Intersection(newFromBounds(c.within[0], c.within[1]))
+
+			members := []string{}
+			err := l.Each(func(d apd.Decimal) bool {
+				members = append(members, d.String())
+				return true
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			r := strings.Join(members, ", ")
+			if r != c.members {
+				t.Fatalf("Expected members '%v', but got '%v'", c.members, r)
+			}
+
+			n, ok := l.Count()
+			if !ok || n.Cmp(big.NewInt(int64(len(members)))) != 0 {
+				t.Fatalf("Expected a count of %v, but got %v", len(members), n)
+			}
+			if l.IsEmpty() != (len(members) == 0) {
+				t.Fatalf("Unexpected emptiness of %v", l.String())
+			}
+			for _, m := range members {
+				if !l.Contains(decimal(m)) {
+					t.Fatalf("Expected %v to contain %v", l.String(), m)
+				}
+			}
+		})
+	}
+}
+
+func TestLatticeContains(t *testing.T) {
+	l := newLattice(t, "0", "0.05").Intersection(newFromBounds(boundDef{"0", false}, boundDef{"10", false}))
+
+	type membership struct {
+		d  string
+		in bool
+	}
+	for _, m := range []membership{
+		{"0", true},
+		{"0.05", true},
+		{"0.050000", true},
+		{"9.95", true},
+		{"10", true},
+		{"10.05", false},
+		{"-0.05", false},
+		{"0.051", false},
+		{"Infinity", false},
+	} {
+		if l.Contains(decimal(m.d)) != m.in {
+			t.Fatalf("Expected membership of %v in %v to be %v", m.d, l.String(), m.in)
+		}
+	}
+
+	if _, ok := newLattice(t, "0", "1").Count(); ok {
+		t.Fatalf("Expected an unrestricted lattice to be infinite")
+	}
+	if _, err := NewLattice(decimal("0"), decimal("0")); err == nil {
+		t.Fatalf("Expected a zero step to be rejected")
+	}
+}
+
+func TestLatticeSnap(t *testing.T) {
+	within := newFromBounds(boundDef{"0", false}, boundDef{"1", false}).
+		Union(newFromBounds(boundDef{"2", false}, boundDef{"3", true}))
+	l := newLattice(t, "0", "0.25").Intersection(within)
+
+	type testcase struct {
+		d, snapped string
+	}
+	for _, c := range []testcase{
+		{"0.3", "0.25"},
+		{"0.375", "0.25"},
+		{"0.4", "0.50"},
+		{"-5", "0.00"},
+		{"1.4", "1.00"},
+		{"1.6", "2.00"},
+		{"1.5", "1.00"},
+		{"2.9", "2.75"},
+		{"100", "2.75"},
+		{"2.5", "2.50"},
+	} {
+		s, ok := l.Snap(decimal(c.d))
+		if !ok || s.String() != c.snapped {
+			t.Fatalf("Expected %v to snap to %v in %v, but got %v", c.d, c.snapped, l.String(), s.String())
+		}
+	}
+
+	empty := newLattice(t, "0", "1").Intersection(newFromBounds(boundDef{"0.2", false}, boundDef{"0.8", false}))
+	if _, ok := empty.Snap(decimal("0.5")); ok {
+		t.Fatalf("Expected snapping to an empty lattice to fail")
+	}
+
+	unrestricted := newLattice(t, "0.01", "0.05")
+	if s, ok := unrestricted.Snap(decimal("-1.234")); !ok || s.String() != "-1.24" {
+		t.Fatalf("Unexpected snap %v", s.String())
+	}
+
+	// Infinities snap to the extreme members, if there are any, and NaN to
+	// nothing.
+	ticks := newLattice(t, "0.1", "0.5").Intersection(newFromBounds(boundDef{"0", false}, boundDef{"10", false}))
+	above := newLattice(t, "0", "1").Intersection(newFromBounds(boundDef{"3", true}, boundDef{"infinity", true}))
+	type infinitecase struct {
+		l       Lattice
+		d       string
+		snapped string
+		ok      bool
+	}
+	for _, c := range []infinitecase{
+		{ticks, "Infinity", "9.6", true},
+		{ticks, "-Infinity", "0.1", true},
+		{ticks, "NaN", "", false},
+		{above, "Infinity", "", false},
+		{above, "-Infinity", "4", true},
+		{unrestricted, "Infinity", "", false},
+		{empty, "-Infinity", "", false},
+	} {
+		s, ok := c.l.Snap(decimal(c.d))
+		if ok != c.ok || ok && s.String() != c.snapped {
+			t.Fatalf("Expected %v to snap to %v, %v in %v, but got %v, %v", c.d, c.snapped, c.ok, c.l.String(), s.String(), ok)
+		}
+	}
+}
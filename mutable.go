@@ -0,0 +1,46 @@
+package apis
+
+import (
+	"github.com/cockroachdb/apd/v3"
+)
+
+// MutableSet is a set of decimals for long-lived sets updated an interval at
+// a time, such as allocated ID ranges. It holds its maximal intervals in a
+// treap, a randomly balanced search tree, so each update takes O(log n)
+// expected time, plus the time to drop any intervals it absorbs or removes
+// entirely. The zero MutableSet is empty and ready to use.
+type MutableSet struct {
+	root *treapNode
+}
+
+// Add adds every number in iv to the set.
+func (m *MutableSet) Add(iv Interval) {
+	m.root = insert(m.root, iv)
+}
+
+// Remove removes every number in iv from the set.
+func (m *MutableSet) Remove(iv Interval) {
+	m.root = remove(m.root, iv)
+}
+
+// Contains reports whether d is in the set.
+func (m *MutableSet) Contains(d apd.Decimal) bool {
+	return treapContains(m.root, d)
+}
+
+// IsEmpty reports whether the set contains no numbers.
+func (m *MutableSet) IsEmpty() bool {
+	return m.root == nil
+}
+
+// Snapshot returns the current contents as a Set, which later updates do not
+// affect.
+func (m *MutableSet) Snapshot() Set {
+	return Set{items: itemsFromIntervals(treapIntervals(m.root))}.own()
+}
+
+// Persistent returns the current contents as a PersistentSet in O(1), as the
+// two share their nodes.
+func (m *MutableSet) Persistent() PersistentSet {
+	return PersistentSet{m.root}
+}
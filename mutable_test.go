@@ -0,0 +1,134 @@
+package apis
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/cockroachdb/apd/v3"
+)
+
+func TestMutableSet(t *testing.T) {
+	type update struct {
+		remove bool
+		b      []boundDef
+	}
+
+	type testcase struct {
+		updates []update
+		result  string
+	}
+
+	cases := []testcase{
+		{[]update{}, ""},
+		{[]update{{false, []boundDef{{"0", false}, {"1", true}}}, {false, []boundDef{{"1", false}, {"2", false}}}}, "[0, 2]"},
+		{[]update{{false, []boundDef{{"0", false}, {"1", true}}}, {false, []boundDef{{"1", true}, {"2", false}}}}, "[0, 1), (1, 2]"},
+		{[]update{
+			{false, []boundDef{{"0", false}, {"1", true}}},
+			{false, []boundDef{{"1", true}, {"2", false}}},
+			{false, []boundDef{{"1", false}, {"1", false}}},
+		}, "[0, 2]"},
+		{[]update{
+			{false, []boundDef{{"0", false}, {"10", false}}},
+			{true, []boundDef{{"5", false}, {"5", false}}},
+		}, "[0, 5), (5, 10]"},
+		{[]update{
+			{false, []boundDef{{"0", false}, {"1", false}}},
+			{false, []boundDef{{"2", false}, {"3", false}}},
+			{false, []boundDef{{"4", false}, {"5", false}}},
+			{true, []boundDef{{"0.5", true}, {"4.5", false}}},
+		}, "[0, 0.5], (4.5, 5]"},
+		{[]update{
+			{false, []boundDef{{"0", false}, {"1", false}}},
+			{false, []boundDef{{"2", false}, {"3", false}}},
+			{false, []boundDef{{"-infinity", true}, {"2.5", false}}},
+		}, "(-Infinity, 3]"},
+		{[]update{
+			{false, []boundDef{{"0", false}, {"1", false}}},
+			{true, []boundDef{{"-infinity", true}, {"infinity", true}}},
+		}, ""},
+	}
+
+	for _, c := range cases {
+		t.Run(c.result, func(t *testing.T) {
+			m := MutableSet{}
+			for _, u := range c.updates {
+				if u.remove {
+					m.Remove(newInterval(u.b[0], u.b[1]))
+				} else {
+					m.Add(newInterval(u.b[0], u.b[1]))
+				}
+			}
+			s := m.Snapshot()
+			if err := s.Validate(); err != nil {
+				t.Fatal(err)
+			}
+			if r := s.String(); r != c.result {
+				t.Fatalf("Expected '%v', but got '%v'", c.result, r)
+			}
+		})
+	}
+}
+
+func TestMutableSetMatchesSet(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	m := MutableSet{}
+	s := Set{}
+	for n := 0; n < 2000; n++ {
+		l := r.Int63n(200)
+		iv := Interval{Lower: *apd.New(l, 0), LowerOpen: r.Intn(2) == 0, Upper: *apd.New(l+r.Int63n(10), 0), UpperOpen: r.Intn(2) == 0}
+		if r.Intn(3) == 0 {
+			m.Remove(iv)
+			s = s.Difference(FromIntervals(iv))
+		} else {
+			m.Add(iv)
+			s = s.Union(FromIntervals(iv))
+		}
+
+		snapshot := m.Snapshot()
+		if !snapshot.Equal(s) {
+			t.Fatalf("Expected '%v', but got '%v'", s.String(), snapshot.String())
+		}
+		d := *apd.New(r.Int63n(420), -1)
+		if m.Contains(d) != s.Contains(d) {
+			t.Fatalf("Expected Contains(%v) to be %v", d.String(), s.Contains(d))
+		}
+	}
+}
+
+func TestMutableSetPersistent(t *testing.T) {
+	m := MutableSet{}
+	m.Add(newInterval(boundDef{"0", false}, boundDef{"10", false}))
+	p := m.Persistent()
+	m.Remove(newInterval(boundDef{"2", false}, boundDef{"3", false}))
+
+	if r := p.String(); r != "[0, 10]" {
+		t.Fatalf("Expected '%v', but got '%v'", "[0, 10]", r)
+	}
+	s := m.Snapshot()
+	if r := s.String(); r != "[0, 2), (3, 10]" {
+		t.Fatalf("Expected '%v', but got '%v'", "[0, 2), (3, 10]", r)
+	}
+}
+
+func BenchmarkIncrementalAdd(b *testing.B) {
+	// Allocate IDs one range at a time, as a long-lived set would.
+	iv := func(i int) Interval {
+		return Interval{Lower: *apd.New(int64(3*i), 0), Upper: *apd.New(int64(3*i+1), 0)}
+	}
+	b.Run("MutableSet", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			m := MutableSet{}
+			for j := 0; j < 10000; j++ {
+				m.Add(iv(j))
+			}
+		}
+	})
+	b.Run("Set", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			s := Set{}
+			for j := 0; j < 10000; j++ {
+				s = s.Union(FromIntervals(iv(j)))
+			}
+		}
+	})
+}
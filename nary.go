@@ -0,0 +1,114 @@
+package apis
+
+import (
+	"container/heap"
+	"context"
+)
+
+// UnionAll returns the union of all the sets, in a single merge over their
+// items rather than by repeated pairwise Union.
+func UnionAll(sets ...Set) Set {
+	s, _ := mergeAll(context.Background(), sets, unionDepth)
+	return s
+}
+
+func unionDepth(depth int) bool {
+	return depth > 0
+}
+
+// IntersectAll returns the intersection of all the sets. The intersection of
+// no sets is every decimal.
+func IntersectAll(sets ...Set) Set {
+	if len(sets) == 0 {
+		return New(negativeInfinity, true, positiveInfinity, true)
+	}
+	s, _ := mergeAll(context.Background(), sets, intersectionDepth(len(sets)))
+	return s
+}
+
+func intersectionDepth(k int) func(int) bool {
+	return func(depth int) bool { return depth == k }
+}
+
+// cursor is the position of the next unmerged item of a set.
+type cursor struct {
+	set int
+	pos int
+}
+
+// cursorHeap orders cursors by the decimal of their next item.
+type cursorHeap struct {
+	sets    []Set
+	cursors []cursor
+}
+
+func (h *cursorHeap) Len() int { return len(h.cursors) }
+
+func (h *cursorHeap) Less(i, j int) bool {
+	a, b := h.cursors[i], h.cursors[j]
+	return h.sets[a.set].items[a.pos].d.Cmp(&h.sets[b.set].items[b.pos].d) < 0
+}
+
+func (h *cursorHeap) Swap(i, j int) { h.cursors[i], h.cursors[j] = h.cursors[j], h.cursors[i] }
+
+func (h *cursorHeap) Push(x any) { h.cursors = append(h.cursors, x.(cursor)) }
+
+func (h *cursorHeap) Pop() any {
+	n := len(h.cursors)
+	c := h.cursors[n-1]
+	h.cursors = h.cursors[:n-1]
+	return c
+}
+
+func (h *cursorHeap) peek() item {
+	c := h.cursors[0]
+	return h.sets[c.set].items[c.pos]
+}
+
+// mergeAll does a k-way merge of every set's items, counting how many sets
+// contain each decimal, and returns the decimals at whose depth keep is true.
+// keep(0) must be false. It takes O(n log k) for n items over k sets, and
+// stops early with an error if ctx is done.
+func mergeAll(ctx context.Context, sets []Set, keep func(depth int) bool) (Set, error) {
+	h := &cursorHeap{sets: sets}
+	for n, s := range sets {
+		if len(s.items) > 0 {
+			h.cursors = append(h.cursors, cursor{n, 0})
+		}
+	}
+	heap.Init(h)
+
+	res := Set{}
+	in := make([]bool, len(sets))
+	depth := 0
+	for n := 0; h.Len() > 0; n++ {
+		if n%1024 == 0 {
+			if err := ctx.Err(); err != nil {
+				return Set{}, err
+			}
+		}
+		first := h.peek()
+		at, after := depth, depth
+		// Each set has at most one item at any decimal.
+		for h.Len() > 0 {
+			if next := h.peek(); next.d.Cmp(&first.d) != 0 {
+				break
+			}
+			c := h.cursors[0]
+			inAt, inAfter := sets[c.set].items[c.pos].step(in[c.set])
+			at += int(count(inAt) - count(in[c.set]))
+			after += int(count(inAfter) - count(in[c.set]))
+			in[c.set] = inAfter
+
+			if c.pos+1 < len(sets[c.set].items) {
+				h.cursors[0].pos++
+				heap.Fix(h, 0)
+			} else {
+				heap.Pop(h)
+			}
+		}
+		res.items = emit(res.items, first.d, keep(depth), keep(at), keep(after))
+		depth = after
+	}
+	return res.own(), nil
+}
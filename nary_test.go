@@ -0,0 +1,78 @@
+package apis
+
+import (
+	"testing"
+)
+
+func TestUnionAllIntersectAll(t *testing.T) {
+	a := newFromBounds(boundDef{"0", false}, boundDef{"10", true})
+	b := newFromBounds(boundDef{"5", true}, boundDef{"15", false})
+	c := newFromBounds(boundDef{"10", false}, boundDef{"10", false})
+	d := newFromBounds(boundDef{"7", false}, boundDef{"7", false}).Complement()
+
+	type testcase struct {
+		sets         []Set
+		union        string
+		intersection string
+	}
+
+	cases := []testcase{
+		{[]Set{}, "", "(-Infinity, Infinity)"},
+		{[]Set{a}, "[0, 10)", "[0, 10)"},
+		{[]Set{a, b}, "[0, 15]", "(5, 10)"},
+		{[]Set{a, b, c}, "[0, 15]", ""},
+		{[]Set{a, b, d}, "(-Infinity, Infinity)", "(5, 7), (7, 10)"},
+		{[]Set{a, c}, "[0, 10]", ""},
+		{[]Set{b, c}, "(5, 15]", "[10, 10]"},
+		{[]Set{d, d, Set{}}, "(-Infinity, 7), (7, Infinity)", ""},
+	}
+
+	for _, c := range cases {
+		t.Run(c.union+" "+c.intersection, func(t *testing.T) {
+			u, i := UnionAll(c.sets...), IntersectAll(c.sets...)
+			if err := u.Validate(); err != nil {
+				t.Fatal(err)
+			}
+			if err := i.Validate(); err != nil {
+				t.Fatal(err)
+			}
+			if r := u.String(); r != c.union {
+				t.Fatalf("Expected union '%v', but got '%v'", c.union, r)
+			}
+			if r := i.String(); r != c.intersection {
+				t.Fatalf("Expected intersection '%v', but got '%v'", c.intersection, r)
+			}
+		})
+	}
+}
+
+// Fuzz UnionAll and IntersectAll against folding Union and Intersection,
+// interpreting each group of five bytes as a set.
+func FuzzUnionAll(f *testing.F) {
+	f.Add([]byte{1, 0, 3, 1, 0, 2, 1, 5, 0, 1, 3, 0, 3, 0, 2})
+	f.Add([]byte{0, 1, 7, 1, 1, 4, 0, 4, 0, 0, 2, 1, 6, 0, 1})
+	f.Fuzz(func(t *testing.T, b []byte) {
+		sets := []Set{}
+		for i := 0; i+4 < len(b); i += 5 {
+			s := New(getValue(b[i]), getOpen(b[i+1]), getValue(b[i+2]), getOpen(b[i+3]))
+			if b[i+4]%2 == 1 {
+				s = s.Complement()
+			}
+			sets = append(sets, s)
+		}
+
+		union := Set{}
+		intersection := New(negativeInfinity, true, positiveInfinity, true)
+		for _, s := range sets {
+			union = union.Union(s)
+			intersection = intersection.Intersection(s)
+		}
+
+		if u := UnionAll(sets...); !u.Equal(union) {
+			t.Fatalf("Expected union '%v', but got '%v'", union.String(), u.String())
+		}
+		if i := IntersectAll(sets...); !i.Equal(intersection) {
+			t.Fatalf("Expected intersection '%v', but got '%v'", intersection.String(), i.String())
+		}
+	})
+}
@@ -0,0 +1,70 @@
+package apis
+
+import (
+	"context"
+	"runtime"
+	"sync"
+)
+
+// UnionAllParallel returns the same set as UnionAll, splitting the sets
+// between up to workers goroutines, or GOMAXPROCS if workers is not
+// positive, and merging their partial unions. It returns ctx's error if ctx
+// is done before the union is complete.
+func UnionAllParallel(ctx context.Context, workers int, sets ...Set) (Set, error) {
+	return reduceParallel(ctx, workers, sets, func(sets []Set) func(int) bool {
+		return unionDepth
+	})
+}
+
+// IntersectAllParallel returns the same set as IntersectAll, as
+// UnionAllParallel does for UnionAll.
+func IntersectAllParallel(ctx context.Context, workers int, sets ...Set) (Set, error) {
+	if len(sets) == 0 {
+		return IntersectAll(), nil
+	}
+	return reduceParallel(ctx, workers, sets, func(sets []Set) func(int) bool {
+		return intersectionDepth(len(sets))
+	})
+}
+
+// reduceParallel merges contiguous chunks of the sets concurrently, then
+// merges the chunks' results. keep returns the depth predicate for merging a
+// list of sets, which must make the reduction associative. As every Set has
+// one canonical form, the result does not depend on how the sets are split.
+func reduceParallel(ctx context.Context, workers int, sets []Set, keep func([]Set) func(int) bool) (Set, error) {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if workers > len(sets) {
+		workers = len(sets)
+	}
+	if workers <= 1 {
+		return mergeAll(ctx, sets, keep(sets))
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	partial := make([]Set, workers)
+	errs := make([]error, workers)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		chunk := sets[w*len(sets)/workers : (w+1)*len(sets)/workers]
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			partial[w], errs[w] = mergeAll(ctx, chunk, keep(chunk))
+			if errs[w] != nil {
+				cancel()
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return Set{}, err
+		}
+	}
+	return mergeAll(ctx, partial, keep(partial))
+}
@@ -0,0 +1,97 @@
+package apis
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/cockroachdb/apd/v3"
+)
+
+// randomSets returns n small intervals with integer bounds in [0, 10n).
+func randomSets(n int, seed int64) []Set {
+	r := rand.New(rand.NewSource(seed))
+	sets := make([]Set, n)
+	for i := range sets {
+		l := r.Int63n(10 * int64(n))
+		sets[i] = New(*apd.New(l, 0), r.Intn(2) == 0, *apd.New(l+r.Int63n(20), 0), r.Intn(2) == 0)
+	}
+	return sets
+}
+
+func TestParallelMatchesSequential(t *testing.T) {
+	sets := randomSets(5000, 1)
+	// Make the intersection interesting, as random intervals rarely overlap.
+	wide := make([]Set, len(sets))
+	for i, s := range sets {
+		wide[i] = s.Union(newFromBounds(boundDef{"-infinity", true}, boundDef{"3", false})).Union(newFromBounds(boundDef{"50", true}, boundDef{"infinity", true}))
+	}
+
+	union, intersection := UnionAll(sets...), IntersectAll(wide...)
+	for _, workers := range []int{0, 1, 2, 3, 7, 64, 10000} {
+		t.Run(fmt.Sprint(workers), func(t *testing.T) {
+			u, err := UnionAllParallel(context.Background(), workers, sets...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !u.Equal(union) {
+				t.Fatalf("Expected parallel union to equal '%v', but got '%v'", union.String(), u.String())
+			}
+			i, err := IntersectAllParallel(context.Background(), workers, wide...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !i.Equal(intersection) {
+				t.Fatalf("Expected parallel intersection to equal '%v', but got '%v'", intersection.String(), i.String())
+			}
+		})
+	}
+
+	if i, _ := IntersectAllParallel(context.Background(), 4); !i.Equal(IntersectAll()) {
+		t.Fatalf("Expected the intersection of no sets to be everything, but got '%v'", i.String())
+	}
+}
+
+func TestParallelCancellation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := UnionAllParallel(ctx, 4, randomSets(1000, 1)...); err != context.Canceled {
+		t.Fatalf("Expected %v, but got %v", context.Canceled, err)
+	}
+	if _, err := IntersectAllParallel(ctx, 1, randomSets(1000, 1)...); err != context.Canceled {
+		t.Fatalf("Expected %v, but got %v", context.Canceled, err)
+	}
+}
+
+func BenchmarkUnionAll(b *testing.B) {
+	sets := randomSets(200000, 1)
+	b.Run("pairwise", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			// Pairwise unions in a balanced tree, as repeated folding is quadratic.
+			level := sets
+			for len(level) > 1 {
+				next := make([]Set, 0, (len(level)+1)/2)
+				for j := 0; j+1 < len(level); j += 2 {
+					next = append(next, level[j].Union(level[j+1]))
+				}
+				if len(level)%2 == 1 {
+					next = append(next, level[len(level)-1])
+				}
+				level = next
+			}
+		}
+	})
+	b.Run("sequential", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			UnionAll(sets...)
+		}
+	})
+	for _, workers := range []int{2, 4, 8} {
+		b.Run(fmt.Sprintf("parallel-%v", workers), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				_, _ = UnionAllParallel(context.Background(), workers, sets...)
+			}
+		})
+	}
+}
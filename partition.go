@@ -0,0 +1,118 @@
+package apis
+
+import (
+	"fmt"
+)
+
+// Bitmask records which of a list of sets contain an Atom, with bit i of
+// word i/64 set if set i does.
+type Bitmask []uint64
+
+func newBitmask(n int) Bitmask {
+	return make(Bitmask, (n+63)/64)
+}
+
+// Has reports whether set i is a member.
+func (m Bitmask) Has(i int) bool {
+	return i/64 < len(m) && m[i/64]&(1<<(i%64)) != 0
+}
+
+func (m Bitmask) set(i int, in bool) {
+	if in {
+		m[i/64] |= 1 << (i % 64)
+	} else {
+		m[i/64] &^= 1 << (i % 64)
+	}
+}
+
+func (m Bitmask) equal(o Bitmask) bool {
+	for i := range m {
+		if m[i] != o[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func (m Bitmask) isZero() bool {
+	for _, w := range m {
+		if w != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func (m Bitmask) key() string {
+	return fmt.Sprint([]uint64(m))
+}
+
+// Members returns the indices of the member sets, in increasing order.
+func (m Bitmask) Members() []int {
+	res := []int{}
+	for i := 0; i < 64*len(m); i++ {
+		if m.Has(i) {
+			res = append(res, i)
+		}
+	}
+	return res
+}
+
+// Atom is a region contained by exactly the sets in Members.
+type Atom struct {
+	Set     Set
+	Members Bitmask
+}
+
+// Partition splits the union of the sets into its finest regions, such that
+// every decimal in a region is contained by the same sets. Atoms are ordered
+// by where they start, and are computed in a single sweep over every set's
+// items. The decimals in none of the sets are not included.
+func Partition(sets ...Set) []Atom {
+	events := sweepEvents(sets)
+
+	atoms := []Atom{}
+	index := map[string]int{}
+	// emitTo appends to the atom for mask, creating it if need be.
+	emitTo := func(mask Bitmask, e item, before, at, after bool) {
+		if mask.isZero() {
+			return
+		}
+		k := mask.key()
+		n, ok := index[k]
+		if !ok {
+			n = len(atoms)
+			index[k] = n
+			atoms = append(atoms, Atom{Members: mask})
+		}
+		atoms[n].Set.items = emit(atoms[n].Set.items, e.d, before, at, after)
+	}
+
+	in := newBitmask(len(sets))
+	for j := 0; j < len(events); {
+		first := events[j].i
+		before := in
+		at, after := append(Bitmask{}, in...), append(Bitmask{}, in...)
+		// Each set has at most one item at any decimal.
+		for ; j < len(events) && events[j].i.d.Cmp(&first.d) == 0; j++ {
+			e := events[j]
+			inAt, inAfter := e.i.step(before.Has(e.set))
+			at.set(e.set, inAt)
+			after.set(e.set, inAfter)
+		}
+
+		// Only the atoms on either side of, or at, this decimal can change here.
+		emitTo(before, first, true, before.equal(at), before.equal(after))
+		if !at.equal(before) {
+			emitTo(at, first, false, true, at.equal(after))
+		}
+		if !after.equal(before) && !after.equal(at) {
+			emitTo(after, first, false, false, true)
+		}
+		in = after
+	}
+	for i := range atoms {
+		atoms[i].Set = atoms[i].Set.own()
+	}
+	return atoms
+}
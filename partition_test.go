@@ -0,0 +1,75 @@
+package apis
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestPartition(t *testing.T) {
+	a := newFromBounds(boundDef{"0", false}, boundDef{"10", true})
+	b := newFromBounds(boundDef{"5", true}, boundDef{"15", false})
+	c := newFromBounds(boundDef{"10", false}, boundDef{"10", false})
+	d := newFromBounds(boundDef{"3", false}, boundDef{"4", false})
+
+	type testcase struct {
+		sets   []Set
+		result string
+	}
+
+	cases := []testcase{
+		{[]Set{}, ""},
+		{[]Set{a, a}, "[0 1]: [0, 10)"},
+		{[]Set{a, b, c}, "[0]: [0, 5]; [0 1]: (5, 10); [1 2]: [10, 10]; [1]: (10, 15]"},
+		{[]Set{a, d}, "[0]: [0, 3), (4, 10); [0 1]: [3, 4]"},
+		{[]Set{a.Complement(), d}, "[0]: (-Infinity, 0), [10, Infinity); [1]: [3, 4]"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.result, func(t *testing.T) {
+			r := ""
+			for i, atom := range Partition(c.sets...) {
+				if err := atom.Set.Validate(); err != nil {
+					t.Fatal(err)
+				}
+				if i > 0 {
+					r += "; "
+				}
+				r += fmt.Sprintf("%v: %v", atom.Members.Members(), atom.Set.String())
+			}
+			if r != c.result {
+				t.Fatalf("Expected '%v', but got '%v'", c.result, r)
+			}
+		})
+	}
+}
+
+func TestPartitionManySets(t *testing.T) {
+	sets := []Set{}
+	for i := 0; i < 70; i++ {
+		n := fmt.Sprint(i)
+		sets = append(sets, newFromBounds(boundDef{n, false}, boundDef{"100", false}))
+	}
+
+	atoms := Partition(sets...)
+	if len(atoms) != 70 {
+		t.Fatalf("Expected 70 atoms, but got %v", len(atoms))
+	}
+	last := atoms[69]
+	if r := last.Set.String(); r != "[69, 100]" {
+		t.Fatalf("Expected '%v', but got '%v'", "[69, 100]", r)
+	}
+	if !last.Members.Has(0) || !last.Members.Has(69) || last.Members.Has(70) || len(last.Members.Members()) != 70 {
+		t.Fatalf("Expected the last atom to be in every set, but got %v", last.Members.Members())
+	}
+
+	union := Set{}
+	for _, atom := range atoms {
+		if !union.Intersection(atom.Set).IsEmpty() {
+			t.Fatalf("Expected atoms to be disjoint")
+		}
+		union = union.Union(atom.Set)
+	}
+	if r := union.String(); r != "[0, 100]" {
+		t.Fatalf("Expected '%v', but got '%v'", "[0, 100]", r)
+	}
+}
@@ -0,0 +1,92 @@
+package apis
+
+import (
+	"github.com/cockroachdb/apd/v3"
+)
+
+// PersistentSet is an immutable set of decimals for keeping many versions of
+// large sets, such as per-tenant policies with small edits. Like MutableSet,
+// it is a treap of maximal intervals, but every operation returns a new
+// version that shares all unchanged subtrees with the original. Versions are
+// cheap to create and safe to use from many goroutines at once.
+//
+// The zero PersistentSet is empty.
+type PersistentSet struct {
+	root *treapNode
+}
+
+// NewPersistent returns a PersistentSet with the same contents as s, in O(n).
+func NewPersistent(s Set) PersistentSet {
+	return PersistentSet{treapFromIntervals(s.Intervals())}
+}
+
+// Set returns the contents as a Set, in O(n).
+func (p PersistentSet) Set() Set {
+	return Set{items: itemsFromIntervals(treapIntervals(p.root))}.own()
+}
+
+// Len returns the number of maximal intervals in the set.
+func (p PersistentSet) Len() int {
+	return p.root.count()
+}
+
+func (p PersistentSet) IsEmpty() bool {
+	return p.root == nil
+}
+
+func (p PersistentSet) Contains(d apd.Decimal) bool {
+	return treapContains(p.root, d)
+}
+
+// Add returns the set with every number in iv added, in O(log n).
+func (p PersistentSet) Add(iv Interval) PersistentSet {
+	return PersistentSet{insert(p.root, iv)}
+}
+
+// Remove returns the set with every number in iv removed, in O(log n).
+func (p PersistentSet) Remove(iv Interval) PersistentSet {
+	return PersistentSet{remove(p.root, iv)}
+}
+
+// Union adds the intervals of the smaller set to the larger, taking
+// O(m log n) for sets of m and n intervals.
+func (p PersistentSet) Union(b PersistentSet) PersistentSet {
+	if p.Len() < b.Len() {
+		p, b = b, p
+	}
+	root := p.root
+	for _, iv := range treapIntervals(b.root) {
+		root = insert(root, iv)
+	}
+	return PersistentSet{root}
+}
+
+// Difference removes the intervals of b from p, taking O(m log n) where b
+// has m intervals. If b is the larger, it is cheaper to rebuild the result,
+// which then shares nothing with p.
+func (p PersistentSet) Difference(b PersistentSet) PersistentSet {
+	if p.Len() < b.Len() {
+		return NewPersistent(p.Set().Difference(b.Set()))
+	}
+	root := p.root
+	for _, iv := range treapIntervals(b.root) {
+		root = remove(root, iv)
+	}
+	return PersistentSet{root}
+}
+
+// Intersection returns the numbers in both sets. It shares nothing with
+// either, and takes O(n + m).
+func (p PersistentSet) Intersection(b PersistentSet) PersistentSet {
+	return NewPersistent(p.Set().Intersection(b.Set()))
+}
+
+// Equal reports whether p and b contain the same numbers.
+func (p PersistentSet) Equal(b PersistentSet) bool {
+	return p.root == b.root || p.Set().Equal(b.Set())
+}
+
+func (p PersistentSet) String() string {
+	s := p.Set()
+	return s.String()
+}
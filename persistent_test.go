@@ -0,0 +1,123 @@
+package apis
+
+import (
+	"math/rand"
+	"sync"
+	"testing"
+
+	"github.com/cockroachdb/apd/v3"
+)
+
+func TestPersistentSetVersions(t *testing.T) {
+	v1 := NewPersistent(newFromBounds(boundDef{"0", false}, boundDef{"10", false}))
+	v2 := v1.Remove(newInterval(boundDef{"5", false}, boundDef{"5", false}))
+	v3 := v2.Add(newInterval(boundDef{"20", true}, boundDef{"30", true}))
+	v4 := v3.Union(NewPersistent(newFromBounds(boundDef{"4", false}, boundDef{"6", false})))
+	v5 := v4.Difference(v3)
+
+	type testcase struct {
+		p      PersistentSet
+		result string
+	}
+
+	cases := []testcase{
+		{PersistentSet{}, ""},
+		{v1, "[0, 10]"},
+		{v2, "[0, 5), (5, 10]"},
+		{v3, "[0, 5), (5, 10], (20, 30)"},
+		{v4, "[0, 10], (20, 30)"},
+		{v5, "[5, 5]"},
+		{v4.Intersection(v2), "[0, 5), (5, 10]"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.result, func(t *testing.T) {
+			s := c.p.Set()
+			if err := s.Validate(); err != nil {
+				t.Fatal(err)
+			}
+			if r := c.p.String(); r != c.result {
+				t.Fatalf("Expected '%v', but got '%v'", c.result, r)
+			}
+		})
+	}
+
+	if !v4.Equal(v4.Union(v1)) || v4.Equal(v3) {
+		t.Fatalf("Expected Equal to compare contents")
+	}
+}
+
+func TestPersistentSetMatchesSet(t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+	randomInterval := func() Interval {
+		l := r.Int63n(500)
+		return Interval{Lower: *apd.New(l, 0), LowerOpen: r.Intn(2) == 0, Upper: *apd.New(l+r.Int63n(10), 0), UpperOpen: r.Intn(2) == 0}
+	}
+
+	p, s := PersistentSet{}, Set{}
+	for n := 0; n < 500; n++ {
+		small := FromIntervals(randomInterval(), randomInterval())
+		switch r.Intn(4) {
+		case 0:
+			p, s = p.Difference(NewPersistent(small)), s.Difference(small)
+		case 1:
+			p, s = NewPersistent(small).Difference(p), small.Difference(s)
+		default:
+			p, s = p.Union(NewPersistent(small)), s.Union(small)
+		}
+		if got := p.Set(); !got.Equal(s) {
+			t.Fatalf("Expected '%v', but got '%v'", s.String(), got.String())
+		}
+		if p.Len() != len(s.Intervals()) {
+			t.Fatalf("Expected %v intervals, but got %v", len(s.Intervals()), p.Len())
+		}
+		d := *apd.New(r.Int63n(5100), -1)
+		if p.Contains(d) != s.Contains(d) {
+			t.Fatalf("Expected Contains(%v) to be %v", d.String(), s.Contains(d))
+		}
+	}
+}
+
+// nodes returns every node of t.
+func nodes(t *treapNode, into map[*treapNode]bool) map[*treapNode]bool {
+	if t != nil {
+		into[t] = true
+		nodes(t.left, into)
+		nodes(t.right, into)
+	}
+	return into
+}
+
+func TestPersistentSetSharing(t *testing.T) {
+	base := NewPersistent(spacedSet(100000))
+	edited := base.Union(NewPersistent(newFromBounds(boundDef{"1001", false}, boundDef{"1003", false}))).
+		Difference(NewPersistent(newFromBounds(boundDef{"50000", false}, boundDef{"50001", false})))
+
+	old := nodes(base.root, map[*treapNode]bool{})
+	fresh := 0
+	for n := range nodes(edited.root, map[*treapNode]bool{}) {
+		if !old[n] {
+			fresh++
+		}
+	}
+	if fresh > 200 {
+		t.Fatalf("Expected a small edit to share most of %v nodes, but %v are new", len(old), fresh)
+	}
+
+	// The original is unchanged, and versions can be edited concurrently.
+	var wg sync.WaitGroup
+	for i := int64(0); i < 8; i++ {
+		wg.Add(1)
+		go func(i int64) {
+			defer wg.Done()
+			v := base.Add(Interval{Lower: *apd.New(1000000+i, 0), Upper: *apd.New(1000000+i, 0)})
+			if !v.Contains(*apd.New(1000000+i, 0)) || v.Len() != base.Len()+1 {
+				t.Errorf("Expected version %v to add a point", i)
+			}
+		}(i)
+	}
+	wg.Wait()
+	if !base.Set().Equal(spacedSet(100000)) {
+		t.Fatalf("Expected the original version to be unchanged")
+	}
+}
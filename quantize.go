@@ -0,0 +1,94 @@
+package apis
+
+import (
+	"math/big"
+
+	"github.com/cockroachdb/apd/v3"
+)
+
+// QuantizeMode selects how Quantize rounds the endpoints of a set.
+type QuantizeMode int
+
+const (
+	// Outward rounds lower bounds down and upper bounds up, so the result is
+	// a superset.
+	Outward QuantizeMode = iota
+	// Inward rounds lower bounds up and upper bounds down, so the result is a
+	// subset.
+	Inward
+	// Nearest rounds every endpoint to the nearest multiple, with ties to
+	// even, keeping its openness.
+	Nearest
+)
+
+// Quantize returns a set whose finite endpoints are all multiples of
+// 10^exponent, expressed with that exponent as apd's Quantize does.
+//
+// Under Outward, an endpoint that has to be rounded keeps its openness, as
+// the rounded value lies outside the interval either way. Under Inward it
+// becomes closed, as the rounded value lies inside, giving the largest subset
+// on the grid. Intervals that collapse to a point, vanish, or become adjacent are handled
+// as if the rounded intervals were unioned.
+func (s Set) Quantize(exponent int32, mode QuantizeMode) Set {
+	lowerRounding, upperRounding := apd.RoundFloor, apd.RoundCeiling
+	switch mode {
+	case Inward:
+		lowerRounding, upperRounding = apd.RoundCeiling, apd.RoundFloor
+	case Nearest:
+		lowerRounding, upperRounding = apd.RoundHalfEven, apd.RoundHalfEven
+	}
+
+	ivs := []Interval{}
+	for _, iv := range s.Intervals() {
+		var exact bool
+		if iv.Lower.Form != apd.Infinite {
+			iv.Lower, exact = quantizeDecimal(iv.Lower, exponent, lowerRounding)
+			iv.LowerOpen = iv.LowerOpen && (exact || mode != Inward)
+		}
+		if iv.Upper.Form != apd.Infinite {
+			iv.Upper, exact = quantizeDecimal(iv.Upper, exponent, upperRounding)
+			iv.UpperOpen = iv.UpperOpen && (exact || mode != Inward)
+		}
+		ivs = append(ivs, iv)
+	}
+	return FromIntervals(ivs...)
+}
+
+// quantizeDecimal rounds a finite decimal to a multiple of 10^exponent with
+// the given rounding, which must be RoundFloor, RoundCeiling or
+// RoundHalfEven. It also reports whether d was already a multiple.
+func quantizeDecimal(d apd.Decimal, exponent int32, rounding apd.Rounder) (apd.Decimal, bool) {
+	// Scale so that the grid is the integers.
+	scaled := ratFromDecimal(d)
+	scaled.Mul(scaled, ratFromDecimal(apd.Decimal{Coeff: *apd.NewBigInt(1), Exponent: -exponent}))
+
+	q, m := floorRat(scaled)
+	exact := m.Sign() == 0
+	if !exact {
+		switch rounding {
+		case apd.RoundCeiling:
+			q.Add(q, big.NewInt(1))
+		case apd.RoundHalfEven:
+			// Compare the remainder to half of the denominator.
+			switch m.Lsh(m, 1).Cmp(scaled.Denom()) {
+			case 1:
+				q.Add(q, big.NewInt(1))
+			case 0:
+				if q.Bit(0) == 1 {
+					q.Add(q, big.NewInt(1))
+				}
+			}
+		}
+	}
+
+	res := decimalFromBigInt(q)
+	res.Exponent = exponent
+	return res, exact
+}
+
+// floorRat returns the largest integer not greater than r, and the
+// non-negative numerator of the remainder over r's denominator.
+func floorRat(r *big.Rat) (*big.Int, *big.Int) {
+	// Euclidean division by a positive denominator floors.
+	return new(big.Int).DivMod(r.Num(), r.Denom(), new(big.Int))
+}
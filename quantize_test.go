@@ -0,0 +1,75 @@
+package apis
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/apd/v3"
+)
+
+func TestQuantize(t *testing.T) {
+	type testcase struct {
+		b        []boundDef
+		exponent int32
+		outward  string
+		inward   string
+		nearest  string
+	}
+
+	cases := []testcase{
+		{[]boundDef{{"0.123", false}, {"0.456", false}, {"0.123", false}, {"0.456", false}}, -2, "[0.12, 0.46]", "[0.13, 0.45]", "[0.12, 0.46]"},
+		{[]boundDef{{"0.123", true}, {"0.456", true}, {"0.123", true}, {"0.456", true}}, -2, "(0.12, 0.46)", "[0.13, 0.45]", "(0.12, 0.46)"},
+		{[]boundDef{{"0.1", true}, {"0.2", true}, {"0.1", true}, {"0.2", true}}, -1, "(0.1, 0.2)", "(0.1, 0.2)", "(0.1, 0.2)"},
+		{[]boundDef{{"0.11", false}, {"0.14", false}, {"0.16", false}, {"0.19", false}}, -1, "[0.1, 0.2]", "", "[0.1, 0.1], [0.2, 0.2]"},
+		{[]boundDef{{"0.12", false}, {"0.2", false}, {"0.12", false}, {"0.2", false}}, -1, "[0.1, 0.2]", "[0.2, 0.2]", "[0.1, 0.2]"},
+		{[]boundDef{{"0.1", false}, {"0.14", true}, {"0.16", true}, {"0.3", false}}, -1, "[0.1, 0.3]", "[0.1, 0.1], [0.2, 0.3]", "(0.2, 0.3]"},
+		{[]boundDef{{"-infinity", true}, {"-0.123", true}, {"12.5", false}, {"infinity", true}}, 0, "(-Infinity, 0), [12, Infinity)", "(-Infinity, -1], [13, Infinity)", "(-Infinity, 0), [12, Infinity)"},
+		{[]boundDef{{"0.15", true}, {"1", true}, {"2", false}, {"2", false}}, -1, "(0.1, 1.0), [2.0, 2.0]", "[0.2, 1.0), [2.0, 2.0]", "(0.2, 1.0), [2.0, 2.0]"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.outward, func(t *testing.T) {
+			s := newFromBounds(c.b[0], c.b[1]).Union(newFromBounds(c.b[2], c.b[3]))
+			for mode, expected := range map[QuantizeMode]string{Outward: c.outward, Inward: c.inward, Nearest: c.nearest} {
+				q := s.Quantize(c.exponent, mode)
+				if err := q.Validate(); err != nil {
+					t.Fatal(err)
+				}
+				r := q.String()
+				if r != expected {
+					t.Fatalf("Expected '%v' quantized with mode %v to be '%v', but got '%v'", s.String(), mode, expected, r)
+				}
+			}
+		})
+	}
+}
+
+func TestQuantizeExclusion(t *testing.T) {
+	type testcase struct {
+		excluded string
+		outward  string
+		inward   string
+		nearest  string
+	}
+
+	cases := []testcase{
+		{"0.55", "[0.0, 1.0]", "[0.0, 0.5], [0.6, 1.0]", "[0.0, 0.6), (0.6, 1.0]"},
+		{"0.5", "[0.0, 0.5), (0.5, 1.0]", "[0.0, 0.5), (0.5, 1.0]", "[0.0, 0.5), (0.5, 1.0]"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.excluded, func(t *testing.T) {
+			d, _, _ := apd.BaseContext.NewFromString(c.excluded)
+			s := newFromBounds(boundDef{"0", false}, boundDef{"1", false}).Intersection(New(*d, false, *d, false).Complement())
+			for mode, expected := range map[QuantizeMode]string{Outward: c.outward, Inward: c.inward, Nearest: c.nearest} {
+				q := s.Quantize(-1, mode)
+				if err := q.Validate(); err != nil {
+					t.Fatal(err)
+				}
+				r := q.String()
+				if r != expected {
+					t.Fatalf("Expected '%v' quantized with mode %v to be '%v', but got '%v'", s.String(), mode, expected, r)
+				}
+			}
+		})
+	}
+}
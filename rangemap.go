@@ -0,0 +1,194 @@
+package apis
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/cockroachdb/apd/v3"
+)
+
+// RangeMap maps disjoint intervals of decimals to values, such as tax brackets
+// or pricing tiers. Touching intervals with equal values are merged, so each
+// entry is a maximal range holding one value.
+//
+// A map is updated in place, so is shared through a pointer, as the
+// constructors return, and must not be copied by value; use Clone to keep a
+// version that later changes do not affect. The zero RangeMap is empty and
+// ready to use, comparing values with ==, which panics on values == cannot
+// compare.
+type RangeMap[V any] struct {
+	equal   func(a, b V) bool
+	entries []rangeEntry[V]
+}
+
+type rangeEntry[V any] struct {
+	iv Interval
+	v  V
+}
+
+// NewRangeMap returns an empty map whose values are compared with ==.
+func NewRangeMap[V comparable]() *RangeMap[V] {
+	return &RangeMap[V]{}
+}
+
+// NewRangeMapFunc returns an empty map whose values are compared with equal,
+// for values, such as apd.Decimal, that == does not compare meaningfully.
+func NewRangeMapFunc[V any](equal func(a, b V) bool) *RangeMap[V] {
+	return &RangeMap[V]{equal: equal}
+}
+
+// Clone returns a copy of the map that later changes to m do not affect.
+func (m *RangeMap[V]) Clone() *RangeMap[V] {
+	return &RangeMap[V]{equal: m.equal, entries: append([]rangeEntry[V](nil), m.entries...)}
+}
+
+func (m *RangeMap[V]) equalValues(a, b V) bool {
+	if m.equal != nil {
+		return m.equal(a, b)
+	}
+	return any(a) == any(b)
+}
+
+// Set maps every point in iv to v, overwriting any values already there.
+func (m *RangeMap[V]) Set(iv Interval, v V) {
+	if iv.IsEmpty() {
+		return
+	}
+	iv = iv.normalize()
+	m.replace(iv, &rangeEntry[V]{iv, v})
+}
+
+// Delete removes every point in iv from the map.
+func (m *RangeMap[V]) Delete(iv Interval) {
+	if iv.IsEmpty() {
+		return
+	}
+	m.replace(iv.normalize(), nil)
+}
+
+// replace removes the points in iv from the map, then adds e, which must
+// cover iv, if it is not nil. Only the entries overlapping iv, and the ones
+// either side that may merge with what replaces them, are touched.
+func (m *RangeMap[V]) replace(iv Interval, e *rangeEntry[V]) {
+	// Entries i to j overlap iv.
+	i := sort.Search(len(m.entries), func(i int) bool {
+		u := m.entries[i].iv
+		c := u.Upper.Cmp(&iv.Lower)
+		return c > 0 || c == 0 && !u.UpperOpen && !iv.LowerOpen
+	})
+	j := sort.Search(len(m.entries), func(j int) bool {
+		l := m.entries[j].iv
+		c := l.Lower.Cmp(&iv.Upper)
+		return c > 0 || c == 0 && (l.LowerOpen || iv.UpperOpen)
+	})
+
+	lo, hi := i, j
+	if lo > 0 {
+		lo--
+	}
+	if hi < len(m.entries) {
+		hi++
+	}
+	window := append([]rangeEntry[V](nil), m.entries[lo:i]...)
+	if i < j {
+		first := m.entries[i]
+		before := Interval{first.iv.Lower, first.iv.LowerOpen, iv.Lower, !iv.LowerOpen}
+		if !before.IsEmpty() {
+			window = append(window, rangeEntry[V]{before, first.v})
+		}
+	}
+	if e != nil {
+		window = append(window, *e)
+	}
+	if i < j {
+		last := m.entries[j-1]
+		after := Interval{iv.Upper, !iv.UpperOpen, last.iv.Upper, last.iv.UpperOpen}
+		if !after.IsEmpty() {
+			window = append(window, rangeEntry[V]{after, last.v})
+		}
+	}
+	window = append(window, m.entries[j:hi]...)
+	window = m.merge(window)
+
+	// Splice the window over entries lo to hi.
+	grow := len(window) - (hi - lo)
+	if grow > 0 {
+		m.entries = append(m.entries, make([]rangeEntry[V], grow)...)
+		copy(m.entries[hi+grow:], m.entries[hi:])
+	} else {
+		copy(m.entries[hi+grow:], m.entries[hi:])
+		m.entries = m.entries[:len(m.entries)+grow]
+	}
+	copy(m.entries[lo:], window)
+}
+
+// merge merges the entries, which must be sorted and disjoint, that touch and
+// have equal values.
+func (m *RangeMap[V]) merge(entries []rangeEntry[V]) []rangeEntry[V] {
+	merged := entries[:0]
+	for _, e := range entries {
+		n := len(merged)
+		if n > 0 {
+			last := &merged[n-1]
+			touching := last.iv.Upper.Cmp(&e.iv.Lower) == 0 && !(last.iv.UpperOpen && e.iv.LowerOpen)
+			if touching && m.equalValues(last.v, e.v) {
+				last.iv.Upper, last.iv.UpperOpen = e.iv.Upper, e.iv.UpperOpen
+				continue
+			}
+		}
+		merged = append(merged, e)
+	}
+	return merged
+}
+
+// Get returns the value at d, or false if d is not in any interval.
+func (m *RangeMap[V]) Get(d apd.Decimal) (V, bool) {
+	// Find the first entry that ends at or above d.
+	i := sort.Search(len(m.entries), func(i int) bool {
+		iv := m.entries[i].iv
+		c := iv.Upper.Cmp(&d)
+		return c > 0 || c == 0 && !iv.UpperOpen
+	})
+	if i < len(m.entries) {
+		iv := m.entries[i].iv
+		c := iv.Lower.Cmp(&d)
+		if c < 0 || c == 0 && !iv.LowerOpen {
+			return m.entries[i].v, true
+		}
+	}
+	var zero V
+	return zero, false
+}
+
+// Len returns the number of maximal intervals in the map.
+func (m *RangeMap[V]) Len() int {
+	return len(m.entries)
+}
+
+// Each calls fn with each interval and its value in increasing order, until fn
+// returns false.
+func (m *RangeMap[V]) Each(fn func(iv Interval, v V) bool) {
+	for _, e := range m.entries {
+		if !fn(e.iv, e.v) {
+			return
+		}
+	}
+}
+
+// Keys returns the set of points that have a value.
+func (m *RangeMap[V]) Keys() Set {
+	ivs := make([]Interval, len(m.entries))
+	for i, e := range m.entries {
+		ivs[i] = e.iv
+	}
+	return FromIntervals(ivs...)
+}
+
+func (m *RangeMap[V]) String() string {
+	parts := []string{}
+	for _, e := range m.entries {
+		parts = append(parts, fmt.Sprintf("%v: %v", formatInterval(e.iv), e.v))
+	}
+	return strings.Join(parts, ", ")
+}
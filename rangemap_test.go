@@ -0,0 +1,199 @@
+package apis
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/cockroachdb/apd/v3"
+)
+
+func TestRangeMapSet(t *testing.T) {
+	type assignment struct {
+		b []boundDef
+		v string
+	}
+
+	type testcase struct {
+		assignments []assignment
+		result      string
+	}
+
+	cases := []testcase{
+		{[]assignment{{[]boundDef{{"0", false}, {"10", true}}, "a"}}, "[0, 10): a"},
+		{[]assignment{
+			{[]boundDef{{"0", false}, {"10", true}}, "a"},
+			{[]boundDef{{"5", false}, {"15", true}}, "b"},
+		}, "[0, 5): a, [5, 15): b"},
+		{[]assignment{
+			{[]boundDef{{"0", false}, {"10", true}}, "a"},
+			{[]boundDef{{"4", false}, {"6", false}}, "b"},
+		}, "[0, 4): a, [4, 6]: b, (6, 10): a"},
+		{[]assignment{
+			{[]boundDef{{"0", false}, {"10", true}}, "a"},
+			{[]boundDef{{"4", false}, {"6", false}}, "b"},
+			{[]boundDef{{"3", false}, {"7", false}}, "a"},
+		}, "[0, 10): a"},
+		{[]assignment{
+			{[]boundDef{{"0", false}, {"1", true}}, "a"},
+			{[]boundDef{{"1", true}, {"2", false}}, "a"},
+		}, "[0, 1): a, (1, 2]: a"},
+		{[]assignment{
+			{[]boundDef{{"-infinity", true}, {"0", true}}, "low"},
+			{[]boundDef{{"0", false}, {"infinity", true}}, "high"},
+			{[]boundDef{{"0", false}, {"0", false}}, "low"},
+		}, "(-Infinity, 0]: low, (0, Infinity): high"},
+		{[]assignment{
+			{[]boundDef{{"0", false}, {"10", false}}, "a"},
+			{[]boundDef{{"5", false}, {"5", false}}, "b"},
+		}, "[0, 5): a, [5, 5]: b, (5, 10]: a"},
+		{[]assignment{{[]boundDef{{"1", true}, {"1", false}}, "a"}}, ""},
+	}
+
+	for _, c := range cases {
+		t.Run(c.result, func(t *testing.T) {
+			m := NewRangeMap[string]()
+			for _, a := range c.assignments {
+				m.Set(newInterval(a.b[0], a.b[1]), a.v)
+			}
+			r := m.String()
+			if r != c.result {
+				t.Fatalf("Expected '%v', but got '%v'", c.result, r)
+			}
+		})
+	}
+}
+
+func TestRangeMapGet(t *testing.T) {
+	brackets := NewRangeMap[int]()
+	brackets.Set(newInterval(boundDef{"0", false}, boundDef{"10000", false}), 10)
+	brackets.Set(newInterval(boundDef{"10000", true}, boundDef{"40000", false}), 20)
+	brackets.Set(newInterval(boundDef{"40000", true}, boundDef{"infinity", true}), 40)
+
+	type testcase struct {
+		d     string
+		v     int
+		found bool
+	}
+
+	cases := []testcase{
+		{"-1", 0, false},
+		{"0", 10, true},
+		{"10000", 10, true},
+		{"10000.01", 20, true},
+		{"40000", 20, true},
+		{"1E+9", 40, true},
+	}
+
+	for _, c := range cases {
+		v, found := brackets.Get(decimal(c.d))
+		if v != c.v || found != c.found {
+			t.Fatalf("Expected Get(%v) to be %v, %v, but got %v, %v", c.d, c.v, c.found, v, found)
+		}
+	}
+
+	brackets.Delete(newInterval(boundDef{"5000", false}, boundDef{"50000", true}))
+	keys := brackets.Keys()
+	if r := keys.String(); r != "[0, 5000), [50000, Infinity)" {
+		t.Fatalf("Expected '%v', but got '%v'", "[0, 5000), [50000, Infinity)", r)
+	}
+	if _, found := brackets.Get(decimal("20000")); found {
+		t.Fatalf("Expected 20000 to be deleted")
+	}
+}
+
+func TestRangeMapEach(t *testing.T) {
+	prices := NewRangeMapFunc(func(a, b apd.Decimal) bool { return a.Cmp(&b) == 0 })
+	prices.Set(newInterval(boundDef{"0", false}, boundDef{"100", true}), decimal("2.50"))
+	prices.Set(newInterval(boundDef{"100", false}, boundDef{"500", true}), decimal("2.5"))
+	prices.Set(newInterval(boundDef{"500", false}, boundDef{"infinity", true}), decimal("2"))
+
+	if prices.Len() != 2 {
+		t.Fatalf("Expected equal prices to merge, but got %v entries", prices.Len())
+	}
+
+	uppers := []string{}
+	prices.Each(func(iv Interval, v apd.Decimal) bool {
+		uppers = append(uppers, iv.Upper.String())
+		return true
+	})
+	if len(uppers) != 2 || uppers[0] != "500" || uppers[1] != "Infinity" {
+		t.Fatalf("Expected uppers 500 and Infinity, but got %v", uppers)
+	}
+
+	// Clones are unaffected by later changes.
+	before := prices.Clone()
+	prices.Set(newInterval(boundDef{"0", false}, boundDef{"1000", false}), decimal("1"))
+	if v, _ := before.Get(decimal("50")); v.String() != "2.50" {
+		t.Fatalf("Expected copy to keep 2.50, but got %v", v.String())
+	}
+}
+
+func TestRangeMapZeroValue(t *testing.T) {
+	var m RangeMap[int]
+	m.Set(newInterval(boundDef{"0", false}, boundDef{"1", false}), 1)
+	m.Set(newInterval(boundDef{"1", false}, boundDef{"2", false}), 1)
+	if r := m.String(); r != "[0, 2]: 1" {
+		t.Fatalf("Expected the zero map to merge equal values with ==, but got '%v'", r)
+	}
+
+	// Maps are shared through pointers, so a copy sees later changes.
+	shared, clone := &m, m.Clone()
+	m.Delete(newInterval(boundDef{"0.5", false}, boundDef{"1.5", false}))
+	if r := shared.String(); r != "[0, 0.5): 1, (1.5, 2]: 1" {
+		t.Fatalf("Expected the shared map to see the deletion, but got '%v'", r)
+	}
+	if r := clone.String(); r != "[0, 2]: 1" {
+		t.Fatalf("Expected the clone to be unaffected, but got '%v'", r)
+	}
+}
+
+func TestRangeMapMatchesModel(t *testing.T) {
+	// The model holds the value at each multiple of 0.5 in [0, 50], with -1
+	// for no value.
+	r := rand.New(rand.NewSource(6))
+	m := NewRangeMap[int]()
+	model := make([]int, 101)
+	for i := range model {
+		model[i] = -1
+	}
+
+	for n := 0; n < 2000; n++ {
+		l := r.Intn(50)
+		iv := Interval{*apd.New(int64(l), 0), r.Intn(2) == 0, *apd.New(int64(l+r.Intn(6)), 0), r.Intn(2) == 0}
+		v := r.Intn(3)
+		if r.Intn(4) == 0 {
+			v = -1
+			m.Delete(iv)
+		} else {
+			m.Set(iv, v)
+		}
+		for i := range model {
+			d := *apd.New(int64(i)*5, -1)
+			if FromIntervals(iv).Contains(d) {
+				model[i] = v
+			}
+		}
+
+		prev := Interval{}
+		for i := 0; i < m.Len(); i++ {
+			e := m.entries[i]
+			if e.iv.IsEmpty() || i > 0 && (e.iv.Lower.Cmp(&prev.Upper) < 0 || e.iv.Lower.Cmp(&prev.Upper) == 0 && !e.iv.LowerOpen && !prev.UpperOpen) {
+				t.Fatalf("Expected sorted, disjoint, non-empty entries, but got '%v'", m.String())
+			}
+			if i > 0 && m.entries[i-1].v == e.v && e.iv.Lower.Cmp(&prev.Upper) == 0 && !(e.iv.LowerOpen && prev.UpperOpen) {
+				t.Fatalf("Expected touching equal values to merge, but got '%v'", m.String())
+			}
+			prev = e.iv
+		}
+		for i, expected := range model {
+			d := *apd.New(int64(i)*5, -1)
+			v, found := m.Get(d)
+			if !found {
+				v = -1
+			}
+			if v != expected {
+				t.Fatalf("Expected %v at %v, but got %v in '%v'", expected, d.String(), v, m.String())
+			}
+		}
+	}
+}
@@ -0,0 +1,437 @@
+package apis
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// endpoint is a bound of an interval set over an arbitrary ordered domain.
+// Set's items are endpoints over apd.Decimal.
+type endpoint[T any] struct {
+	b    bound
+	d    T
+	open bool
+}
+
+// ranges is an interval set over an arbitrary totally ordered domain.
+//
+// Set spells out unbounded intervals with apd infinities, which most domains
+// do not have. Instead, ranges records whether the set extends to the bottom
+// of the domain in below; the set extends to the top of the domain when the
+// last endpoint leaves it in-set. Endpoints otherwise follow the same rules
+// as Set's items, including 'both' acting as an exclusion while in-set and an
+// inclusion while out-of-set.
+type ranges[T any] struct {
+	below bool
+	items []endpoint[T]
+}
+
+// step returns whether the endpoint's value is in-set, and whether values
+// just above it are in-set, given whether values just below it are in-set.
+func (e endpoint[T]) step(before bool) (at bool, after bool) {
+	switch e.b {
+	case lower:
+		return !e.open, true
+	case upper:
+		return !e.open, false
+	default:
+		return !before, before
+	}
+}
+
+// emit appends the canonical endpoint, if any, for a value whose
+// neighbourhood has the given membership.
+func emit[T any](items []endpoint[T], d T, before, at, after bool) []endpoint[T] {
+	switch {
+	case !before && after:
+		return append(items, endpoint[T]{lower, d, !at})
+	case before && !after:
+		return append(items, endpoint[T]{upper, d, !at})
+	case before != at:
+		// Exclusion within, or inclusion outside of, the surrounding set.
+		return append(items, endpoint[T]{both, d, false})
+	}
+	return items
+}
+
+// combine sweeps both sets in a single pass, evaluating op at and between
+// every endpoint. The result is canonical whenever op(false, false) reflects
+// the intended behaviour below and above all endpoints.
+func combine[T any](a, b ranges[T], cmp func(T, T) int, op func(bool, bool) bool) ranges[T] {
+	return combineInto(nil, a, b, cmp, op)
+}
+
+// combineInto is combine, appending the result's endpoints to dst[:0]. dst
+// must not share memory with the endpoints of a or b that are still to be
+// read.
+func combineInto[T any](dst []endpoint[T], a, b ranges[T], cmp func(T, T) int, op func(bool, bool) bool) ranges[T] {
+	res := ranges[T]{below: op(a.below, b.below), items: dst[:0]}
+	inA, inB := a.below, b.below
+	ai, bi := 0, 0
+	for ai < len(a.items) || bi < len(b.items) {
+		// A run of one set's endpoints before the other's next endpoint lies
+		// where the other's membership is fixed. If op then copies or ignores
+		// the first set, the whole run can be found by galloping and copied or
+		// skipped at once, so small sets combine with large ones quickly.
+		if ai < len(a.items) && (bi == len(b.items) || cmp(a.items[ai].d, b.items[bi].d) < 0) {
+			end := len(a.items)
+			if bi < len(b.items) {
+				end = gallop(a.items, ai, b.items[bi].d, cmp)
+			}
+			var ok bool
+			if res.items, inA, ok = applyRun(res.items, a.items[ai:end], inA, op(false, inB), op(true, inB)); ok {
+				ai = end
+				continue
+			}
+		} else if bi < len(b.items) && (ai == len(a.items) || cmp(b.items[bi].d, a.items[ai].d) < 0) {
+			end := len(b.items)
+			if ai < len(a.items) {
+				end = gallop(b.items, bi, a.items[ai].d, cmp)
+			}
+			var ok bool
+			if res.items, inB, ok = applyRun(res.items, b.items[bi:end], inB, op(inA, false), op(inA, true)); ok {
+				bi = end
+				continue
+			}
+		}
+
+		var d T
+		var c int
+		switch {
+		case ai >= len(a.items):
+			c = 1
+		case bi >= len(b.items):
+			c = -1
+		default:
+			c = cmp(a.items[ai].d, b.items[bi].d)
+		}
+
+		atA, afterA := inA, inA
+		atB, afterB := inB, inB
+		if c <= 0 {
+			d = a.items[ai].d
+			atA, afterA = a.items[ai].step(inA)
+			ai++
+		}
+		if c >= 0 {
+			d = b.items[bi].d
+			atB, afterB = b.items[bi].step(inB)
+			bi++
+		}
+
+		res.items = emit(res.items, d, op(inA, inB), op(atA, atB), op(afterA, afterB))
+		inA, inB = afterA, afterB
+	}
+	return res
+}
+
+// gallop returns the index of the first endpoint from i on that is not
+// below bound, where items[i] is below it. It searches exponentially, then
+// binarily, so takes O(log k) to skip k endpoints.
+func gallop[T any](items []endpoint[T], i int, bound T, cmp func(T, T) int) int {
+	// Endpoints up to lo are below bound; those from hi on are not.
+	lo, hi := i, i+1
+	for step := 1; hi < len(items) && cmp(items[hi].d, bound) < 0; step *= 2 {
+		lo, hi = hi, hi+step
+	}
+	if hi > len(items) {
+		hi = len(items)
+	}
+	for lo+1 < hi {
+		mid := lo + (hi-lo)/2
+		if cmp(items[mid].d, bound) < 0 {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	return hi
+}
+
+// applyRun appends the result of a run of endpoints over which the other
+// set's membership is fixed, given the results f0 and f1 of op when the run's
+// set is out of and in set. It returns false, appending nothing, if op
+// negates the run's set, which needs every endpoint inverting.
+//
+// When combining in place the run may lie in dst's spare capacity, so the
+// run is read in full before anything is appended.
+func applyRun[T any](dst []endpoint[T], run []endpoint[T], in bool, f0, f1 bool) ([]endpoint[T], bool, bool) {
+	if f0 && !f1 {
+		return dst, in, false
+	}
+	after := runAfter(run, in)
+	if !f0 && f1 {
+		dst = append(dst, run...)
+	}
+	return dst, after, true
+}
+
+// runAfter returns the membership after a run of endpoints, given the
+// membership before it, which is set by the run's last lower or upper bound.
+func runAfter[T any](run []endpoint[T], in bool) bool {
+	for j := len(run) - 1; j >= 0; j-- {
+		if run[j].b != both {
+			return run[j].b == lower
+		}
+	}
+	return in
+}
+
+// isSubsetOf sweeps both sets as combine does, stopping at the first value
+// in a but not in b. In canonical sets every endpoint changes membership at
+// or beside its value, so an endpoint of b where a is in-set, or of a where b
+// is out-of-set, is enough to rule out a subset, and the runs between are
+// galloped over.
+func (a ranges[T]) isSubsetOf(b ranges[T], cmp func(T, T) int) bool {
+	inA, inB := a.below, b.below
+	if inA && !inB {
+		return false
+	}
+	ai, bi := 0, 0
+	for ai < len(a.items) || bi < len(b.items) {
+		var c int
+		switch {
+		case ai >= len(a.items):
+			c = 1
+		case bi >= len(b.items):
+			c = -1
+		default:
+			c = cmp(a.items[ai].d, b.items[bi].d)
+		}
+
+		switch {
+		case c < 0:
+			if !inB {
+				return false
+			}
+			end := len(a.items)
+			if bi < len(b.items) {
+				end = gallop(a.items, ai, b.items[bi].d, cmp)
+			}
+			inA, ai = runAfter(a.items[ai:end], inA), end
+		case c > 0:
+			if inA {
+				return false
+			}
+			end := len(b.items)
+			if ai < len(a.items) {
+				end = gallop(b.items, bi, a.items[ai].d, cmp)
+			}
+			inB, bi = runAfter(b.items[bi:end], inB), end
+		default:
+			atA, afterA := a.items[ai].step(inA)
+			atB, afterB := b.items[bi].step(inB)
+			if atA && !atB || afterA && !afterB {
+				return false
+			}
+			inA, inB = afterA, afterB
+			ai++
+			bi++
+		}
+	}
+	return true
+}
+
+func (a ranges[T]) union(b ranges[T], cmp func(T, T) int) ranges[T] {
+	return combine(a, b, cmp, func(x, y bool) bool { return x || y })
+}
+
+func (a ranges[T]) intersection(b ranges[T], cmp func(T, T) int) ranges[T] {
+	return combine(a, b, cmp, func(x, y bool) bool { return x && y })
+}
+
+func (a ranges[T]) difference(b ranges[T], cmp func(T, T) int) ranges[T] {
+	return combine(a, b, cmp, func(x, y bool) bool { return x && !y })
+}
+
+// complement flips membership everywhere, which for this representation
+// turns every lower bound into an upper bound and vice versa.
+func (a ranges[T]) complement() ranges[T] {
+	res := ranges[T]{
+		below: !a.below,
+		items: make([]endpoint[T], len(a.items)),
+	}
+	for i, v := range a.items {
+		switch v.b {
+		case lower:
+			res.items[i] = endpoint[T]{upper, v.d, !v.open}
+		case upper:
+			res.items[i] = endpoint[T]{lower, v.d, !v.open}
+		default:
+			res.items[i] = v
+		}
+	}
+	return res
+}
+
+func (a ranges[T]) isEmpty() bool {
+	return !a.below && len(a.items) == 0
+}
+
+func (a ranges[T]) contains(d T, cmp func(T, T) int) bool {
+	// First endpoint at or above d.
+	i := sort.Search(len(a.items), func(i int) bool {
+		return cmp(a.items[i].d, d) >= 0
+	})
+	in := a.inBefore(i)
+	if i < len(a.items) && cmp(a.items[i].d, d) == 0 {
+		at, _ := a.items[i].step(in)
+		return at
+	}
+	return in
+}
+
+// inBefore returns whether the values just below item i are in-set. Runs of
+// 'both' items do not change membership, so only the nearest bound matters.
+func (a ranges[T]) inBefore(i int) bool {
+	for j := i; j < len(a.items); j++ {
+		if a.items[j].b != both {
+			return a.items[j].b == upper
+		}
+	}
+	for j := i - 1; j >= 0; j-- {
+		if a.items[j].b != both {
+			return a.items[j].b == lower
+		}
+	}
+	return a.below
+}
+
+func (a ranges[T]) validate(cmp func(T, T) int) error {
+	in := a.below
+	for i, v := range a.items {
+		if i > 0 && cmp(a.items[i-1].d, v.d) >= 0 {
+			return fmt.Errorf("%v is not less than %v", v.d, a.items[i-1].d)
+		}
+		switch v.b {
+		case lower:
+			if in {
+				return fmt.Errorf("%v/%v is a lower bound on an interval, but values below the bound are in-set as well.", i, v.d)
+			}
+			in = true
+		case upper:
+			if !in {
+				return fmt.Errorf("%v/%v is an upper bound on an interval, but values below the bound are out-of-set as well.", i, v.d)
+			}
+			in = false
+		case both:
+			if v.open {
+				return fmt.Errorf("%v/%v is an exclusion or inclusion, so cannot be open", i, v.d)
+			}
+		}
+	}
+	return nil
+}
+
+// format renders the set in the same notation as Set.String, using bottom
+// and top for the ends of the domain.
+func (a ranges[T]) format(str func(T) string, bottom, top string) string {
+	b := strings.Builder{}
+	in := a.below
+	if in {
+		b.WriteString("(" + bottom + ", ")
+	}
+	for i, v := range a.items {
+		d := str(v.d)
+		switch v.b {
+		case lower:
+			if i > 0 || a.below {
+				b.WriteString(", ")
+			}
+			if v.open {
+				b.WriteString("(")
+			} else {
+				b.WriteString("[")
+			}
+			b.WriteString(d + ", ")
+			in = true
+		case upper:
+			b.WriteString(d)
+			if v.open {
+				b.WriteString(")")
+			} else {
+				b.WriteString("]")
+			}
+			in = false
+		case both:
+			if in {
+				b.WriteString(d + "), (" + d + ", ")
+			} else {
+				if i > 0 || a.below {
+					b.WriteString(", ")
+				}
+				b.WriteString("[" + d + ", " + d + "]")
+			}
+		}
+	}
+	if in {
+		b.WriteString(top + ")")
+	}
+	return b.String()
+}
+
+// interval builds the ranges between l and u, ordering the bounds as New does.
+func interval[T any](l T, lOpen bool, u T, uOpen bool, cmp func(T, T) int) ranges[T] {
+	c := cmp(l, u)
+	switch {
+	case c == 0:
+		if lOpen || uOpen {
+			return ranges[T]{}
+		}
+		return ranges[T]{items: []endpoint[T]{{both, l, false}}}
+	case c > 0:
+		l, lOpen, u, uOpen = u, uOpen, l, lOpen
+	}
+	return ranges[T]{items: []endpoint[T]{{lower, l, lOpen}, {upper, u, uOpen}}}
+}
+
+// atLeast builds the ranges from l to the top of the domain.
+func atLeast[T any](l T, open bool) ranges[T] {
+	return ranges[T]{items: []endpoint[T]{{lower, l, open}}}
+}
+
+// atMost builds the ranges from the bottom of the domain to u.
+func atMost[T any](u T, open bool) ranges[T] {
+	return ranges[T]{below: true, items: []endpoint[T]{{upper, u, open}}}
+}
+
+// piece is one maximal connected interval of a ranges. Pieces extending to
+// the bottom or top of the domain have no lower or upper bound respectively.
+type piece[T any] struct {
+	l, u         T
+	lOpen, uOpen bool
+	bottom, top  bool
+}
+
+// pieces decomposes the ranges into its maximal connected intervals, in
+// increasing order, as Set.Intervals does.
+func (a ranges[T]) pieces() []piece[T] {
+	res := []piece[T]{}
+	cur := piece[T]{bottom: true}
+	in := a.below
+	for _, v := range a.items {
+		switch v.b {
+		case lower:
+			cur = piece[T]{l: v.d, lOpen: v.open}
+			in = true
+		case upper:
+			cur.u, cur.uOpen = v.d, v.open
+			res = append(res, cur)
+			in = false
+		case both:
+			if in {
+				cur.u, cur.uOpen = v.d, true
+				res = append(res, cur)
+				cur = piece[T]{l: v.d, lOpen: true}
+			} else {
+				res = append(res, piece[T]{l: v.d, u: v.d})
+			}
+		}
+	}
+	if in {
+		cur.top = true
+		res = append(res, cur)
+	}
+	return res
+}
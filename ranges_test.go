@@ -0,0 +1,193 @@
+package apis
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/cockroachdb/apd/v3"
+)
+
+// spacedSet returns n/2 disjoint intervals [4k, 4k+2] spread over [0, 2n),
+// with every third one open at both ends and every fifth one excluding its
+// midpoint, giving about n items.
+func spacedSet(n int) Set {
+	ivs := []Interval{}
+	for k := int64(0); k < int64(n/2); k++ {
+		open := k%3 == 0
+		iv := Interval{Lower: *apd.New(4*k, 0), LowerOpen: open, Upper: *apd.New(4*k+2, 0), UpperOpen: open}
+		if k%5 == 0 {
+			left, right := iv, iv
+			left.Upper, left.UpperOpen = *apd.New(4*k+1, 0), true
+			right.Lower, right.LowerOpen = *apd.New(4*k+1, 0), true
+			ivs = append(ivs, left, right)
+			continue
+		}
+		ivs = append(ivs, iv)
+	}
+	return FromIntervals(ivs...)
+}
+
+// probes returns every item of the sets, and a value between and beyond
+// each of them.
+func probes(sets ...Set) []apd.Decimal {
+	res := []apd.Decimal{*apd.New(-1, 9)}
+	for _, s := range sets {
+		for _, i := range s.items {
+			if i.d.Form != apd.Finite {
+				continue
+			}
+			var above apd.Decimal
+			_, _ = apd.BaseContext.Add(&above, &i.d, apd.New(1, -1))
+			res = append(res, i.d, above)
+		}
+	}
+	return res
+}
+
+func TestLopsidedOperations(t *testing.T) {
+	big := spacedSet(2000)
+	smalls := []Set{
+		Set{},
+		newFromBounds(boundDef{"1001", false}, boundDef{"1003", true}),
+		newFromBounds(boundDef{"20", true}, boundDef{"22", false}).Union(newFromBounds(boundDef{"3000", false}, boundDef{"3000", false})),
+		newFromBounds(boundDef{"40", false}, boundDef{"2600", true}),
+		newFromBounds(boundDef{"41", false}, boundDef{"41", false}).Complement(),
+		newFromBounds(boundDef{"-infinity", true}, boundDef{"50", false}).Union(newFromBounds(boundDef{"3990", true}, boundDef{"infinity", true})),
+	}
+
+	type op struct {
+		name string
+		fn   func(a, b Set) Set
+		in   func(inA, inB bool) bool
+	}
+
+	ops := []op{
+		{"union", Set.Union, opUnion},
+		{"intersection", Set.Intersection, opIntersection},
+		{"difference", Set.Difference, opDifference},
+		{"symmetric difference", Set.SymmetricDifference, opSymmetricDifference},
+	}
+
+	for i, small := range smalls {
+		for _, pair := range [][2]Set{{big, small}, {small, big}} {
+			a, b := pair[0], pair[1]
+			ps := probes(a, b)
+			for _, o := range ops {
+				t.Run(fmt.Sprintf("%v/%v", o.name, i), func(t *testing.T) {
+					s := o.fn(a, b)
+					if err := s.Validate(); err != nil {
+						t.Fatal(err)
+					}
+					for _, p := range ps {
+						if s.Contains(p) != o.in(a.Contains(p), b.Contains(p)) {
+							t.Fatalf("Expected %v to be in the %v only if in one of the inputs", p.String(), o.name)
+						}
+					}
+				})
+			}
+			if a.IsSubsetOf(b) != a.Difference(b).IsEmpty() {
+				t.Fatalf("Expected IsSubsetOf to agree with Difference")
+			}
+		}
+	}
+
+	within := newFromBounds(boundDef{"4.5", false}, boundDef{"5.5", false}).Union(newFromBounds(boundDef{"3000", true}, boundDef{"3001", true}))
+	if !within.IsSubsetOf(big) || big.IsSubsetOf(within) || !big.IsSubsetOf(big) {
+		t.Fatalf("Expected '%v' to be a subset of the spaced set, but not the reverse", within.String())
+	}
+}
+
+func TestIsSubsetOf(t *testing.T) {
+	r := rand.New(rand.NewSource(7))
+	random := func() Set {
+		s := Set{}
+		for i := r.Intn(3); i >= 0; i-- {
+			l := r.Int63n(20)
+			s = s.Union(New(*apd.New(l, 0), r.Intn(2) == 0, *apd.New(l+r.Int63n(8), 0), r.Intn(2) == 0))
+		}
+		for i := r.Intn(3); i >= 0; i-- {
+			d := *apd.New(r.Int63n(20), 0)
+			if r.Intn(2) == 0 {
+				s = s.Union(New(d, false, d, false))
+			} else {
+				s = s.Difference(New(d, false, d, false))
+			}
+		}
+		if r.Intn(3) == 0 {
+			s = s.Complement()
+		}
+		return s
+	}
+
+	for n := 0; n < 20000; n++ {
+		a, b := random(), random()
+		if r.Intn(2) == 0 {
+			// Subsets are rare among random pairs.
+			a = a.Intersection(b)
+		}
+		if a.IsSubsetOf(b) != a.Difference(b).IsEmpty() {
+			t.Fatalf("Expected '%v' IsSubsetOf '%v' to be %v", a.String(), b.String(), !a.IsSubsetOf(b))
+		}
+	}
+
+	// Neither answer builds anything, even where one set has many items
+	// inside the other's intervals.
+	big, wide := spacedSet(100000), newFromBounds(boundDef{"-1", false}, boundDef{"1E+6", false})
+	if wide.IsSubsetOf(big) || !big.IsSubsetOf(wide) {
+		t.Fatalf("Expected only the spaced set to be a subset of '%v'", wide.String())
+	}
+	for _, fn := range []func(){func() { wide.IsSubsetOf(big) }, func() { big.IsSubsetOf(wide) }} {
+		if allocs := testing.AllocsPerRun(10, fn); allocs != 0 {
+			t.Fatalf("Expected IsSubsetOf not to allocate, but got %v allocations", allocs)
+		}
+	}
+}
+
+func TestGallop(t *testing.T) {
+	items := spacedSet(100).items
+	for i := range items {
+		for j := i + 1; j <= len(items); j++ {
+			bound := *apd.New(1000, 0)
+			if j < len(items) {
+				bound = items[j].d
+			}
+			if r := gallop(items, i, bound, compareDecimals); r != j {
+				t.Fatalf("Expected gallop from %v to %v to return %v, but got %v", i, bound.String(), j, r)
+			}
+		}
+	}
+}
+
+func BenchmarkLopsided(b *testing.B) {
+	big := spacedSet(1000000)
+	small := spacedSet(10)
+	mid := newFromBounds(boundDef{"1000000", false}, boundDef{"1000010", false})
+	for k := int64(1); k < 5; k++ {
+		mid = mid.Union(New(*apd.New(k*300000+1, 0), false, *apd.New(k*300000+2, 0), false))
+	}
+
+	type benchmark struct {
+		name string
+		fn   func()
+	}
+
+	benchmarks := []benchmark{
+		{"Intersection/small-big", func() { small.Intersection(big) }},
+		{"Intersection/big-small", func() { big.Intersection(mid) }},
+		{"Difference/small-big", func() { mid.Difference(big) }},
+		{"IsSubsetOf/small-big", func() { mid.IsSubsetOf(big) }},
+		{"IsSubsetOf/big-small", func() { big.IsSubsetOf(mid) }},
+		{"Union/small-big", func() { mid.Union(big) }},
+		{"UnionWith/big-small", func() { s := Set{items: big.items[:len(big.items):len(big.items)]}; s.UnionWith(mid) }},
+	}
+
+	for _, bm := range benchmarks {
+		b.Run(bm.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				bm.fn()
+			}
+		})
+	}
+}
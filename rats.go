@@ -0,0 +1,206 @@
+package apis
+
+import (
+	"math/big"
+
+	"github.com/cockroachdb/apd/v3"
+)
+
+// RatSet is a set of rational numbers with exact big.Rat endpoints, for sets
+// whose bounds, like 1/3, have no finite decimal representation.
+//
+// Endpoints are never mutated once in a set, so they may be shared between
+// sets.
+type RatSet struct {
+	r ranges[*big.Rat]
+}
+
+func compareRats(a, b *big.Rat) int {
+	return a.Cmp(b)
+}
+
+// NewRat returns the rationals between l and u. A nil bound is unbounded.
+func NewRat(l *big.Rat, lOpen bool, u *big.Rat, uOpen bool) RatSet {
+	switch {
+	case l == nil && u == nil:
+		return RatSet{ranges[*big.Rat]{below: true}}
+	case l == nil:
+		return RatSet{atMost(new(big.Rat).Set(u), uOpen)}
+	case u == nil:
+		return RatSet{atLeast(new(big.Rat).Set(l), lOpen)}
+	}
+	return RatSet{interval(new(big.Rat).Set(l), lOpen, new(big.Rat).Set(u), uOpen, compareRats)}
+}
+
+// RatFromSet returns the rationals in s. As every decimal is rational, the
+// conversion is exact.
+func RatFromSet(s Set) RatSet {
+	res := ranges[*big.Rat]{}
+	for i, v := range s.items {
+		switch {
+		case i == 0 && isNegativeInfinity(v.d):
+			res.below = true
+		case isPositiveInfinity(v.d):
+			// Unbounded above, implied by the preceding lower bound.
+		default:
+			res.items = append(res.items, endpoint[*big.Rat]{v.b, ratFromDecimal(v.d), v.open})
+		}
+	}
+	return RatSet{res}
+}
+
+func ratFromDecimal(d apd.Decimal) *big.Rat {
+	r := new(big.Rat).SetInt(d.Coeff.MathBigInt())
+	if d.Exponent != 0 {
+		exp := int64(d.Exponent)
+		if exp < 0 {
+			exp = -exp
+		}
+		p := new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(exp), nil))
+		if d.Exponent > 0 {
+			r.Mul(r, p)
+		} else {
+			r.Quo(r, p)
+		}
+	}
+	if d.Negative {
+		r.Neg(r)
+	}
+	return r
+}
+
+// decimalFromRat rounds r to a decimal with the given number of significant
+// digits, in the given direction.
+func decimalFromRat(r *big.Rat, precision uint32, rounding apd.Rounder) (apd.Decimal, error) {
+	d, err := roundRat(r, precision, rounding)
+	if err != nil {
+		return d, err
+	}
+	return trimZeros(d), nil
+}
+
+// roundRat is decimalFromRat, leaving any trailing zeros in place.
+func roundRat(r *big.Rat, precision uint32, rounding apd.Rounder) (apd.Decimal, error) {
+	ctx := apd.BaseContext.WithPrecision(precision)
+	ctx.Rounding = rounding
+	num := decimalFromBigInt(r.Num())
+	den := decimalFromBigInt(r.Denom())
+	var d apd.Decimal
+	_, err := ctx.Quo(&d, &num, &den)
+	return d, err
+}
+
+// trimZeros removes trailing zeros after the decimal point, without switching
+// integers to exponent notation.
+func trimZeros(d apd.Decimal) apd.Decimal {
+	d.Reduce(&d)
+	if d.Exponent > 0 {
+		var scale apd.BigInt
+		scale.Exp(apd.NewBigInt(10), apd.NewBigInt(int64(d.Exponent)), nil)
+		d.Coeff.Mul(&d.Coeff, &scale)
+		d.Exponent = 0
+	}
+	return d
+}
+
+// ToSet returns a decimal set containing a, with endpoints rounded outwards
+// to the given number of significant digits. A rounded endpoint lies outside
+// the original interval, so keeps its openness; excluded points that cannot
+// be represented are dropped.
+func (a RatSet) ToSet(precision uint32) (Set, error) {
+	ivs := []Interval{}
+	for _, p := range a.r.pieces() {
+		iv := Interval{Lower: negativeInfinity, LowerOpen: true, Upper: positiveInfinity, UpperOpen: true}
+		if !p.bottom {
+			l, err := decimalFromRat(p.l, precision, apd.RoundFloor)
+			if err != nil {
+				return Set{}, err
+			}
+			iv.Lower, iv.LowerOpen = l, p.lOpen
+		}
+		if !p.top {
+			u, err := decimalFromRat(p.u, precision, apd.RoundCeiling)
+			if err != nil {
+				return Set{}, err
+			}
+			iv.Upper, iv.UpperOpen = u, p.uOpen
+		}
+		ivs = append(ivs, iv)
+	}
+	return FromIntervals(ivs...), nil
+}
+
+func (a RatSet) Union(b RatSet) RatSet {
+	return RatSet{a.r.union(b.r, compareRats)}
+}
+
+func (a RatSet) Intersection(b RatSet) RatSet {
+	return RatSet{a.r.intersection(b.r, compareRats)}
+}
+
+func (a RatSet) Difference(b RatSet) RatSet {
+	return RatSet{a.r.difference(b.r, compareRats)}
+}
+
+func (a RatSet) Complement() RatSet {
+	return RatSet{a.r.complement()}
+}
+
+func (a RatSet) Contains(x *big.Rat) bool {
+	return a.r.contains(x, compareRats)
+}
+
+func (a RatSet) IsEmpty() bool {
+	return a.r.isEmpty()
+}
+
+func (a RatSet) Validate() error {
+	return a.r.validate(compareRats)
+}
+
+func (a RatSet) String() string {
+	return a.r.format((*big.Rat).RatString, "-Infinity", "Infinity")
+}
+
+// Translate returns {x + k | x in a}.
+func (a RatSet) Translate(k *big.Rat) RatSet {
+	res := RatSet{ranges[*big.Rat]{below: a.r.below, items: make([]endpoint[*big.Rat], len(a.r.items))}}
+	for i, v := range a.r.items {
+		res.r.items[i] = endpoint[*big.Rat]{v.b, new(big.Rat).Add(v.d, k), v.open}
+	}
+	return res
+}
+
+// Scale returns {x * k | x in a}. Scaling by a negative number reverses the
+// order of the set, and scaling by zero collapses it to zero.
+func (a RatSet) Scale(k *big.Rat) RatSet {
+	switch k.Sign() {
+	case 0:
+		if a.IsEmpty() {
+			return a
+		}
+		return NewRat(k, false, k, false)
+	case 1:
+		res := RatSet{ranges[*big.Rat]{below: a.r.below, items: make([]endpoint[*big.Rat], len(a.r.items))}}
+		for i, v := range a.r.items {
+			res.r.items[i] = endpoint[*big.Rat]{v.b, new(big.Rat).Mul(v.d, k), v.open}
+		}
+		return res
+	}
+
+	// Walk the set backwards, so that values above the last endpoint end up
+	// below the first.
+	n := len(a.r.items)
+	res := RatSet{ranges[*big.Rat]{below: a.r.inBefore(n), items: make([]endpoint[*big.Rat], n)}}
+	for i, v := range a.r.items {
+		b := v.b
+		switch b {
+		case lower:
+			b = upper
+		case upper:
+			b = lower
+		}
+		res.r.items[n-1-i] = endpoint[*big.Rat]{b, new(big.Rat).Mul(v.d, k), v.open}
+	}
+	return res
+}
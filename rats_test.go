@@ -0,0 +1,93 @@
+package apis
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestRatSetOperations(t *testing.T) {
+	third := big.NewRat(1, 3)
+	twoThirds := big.NewRat(2, 3)
+
+	a := NewRat(third, false, twoThirds, true)
+	b := NewRat(big.NewRat(1, 2), true, nil, false)
+
+	type testcase struct {
+		result string
+		s      RatSet
+	}
+
+	cases := []testcase{
+		{"[1/3, 2/3)", a},
+		{"(1/2, Infinity)", b},
+		{"[1/3, Infinity)", a.Union(b)},
+		{"(1/2, 2/3)", a.Intersection(b)},
+		{"[1/3, 1/2]", a.Difference(b)},
+		{"(-Infinity, 1/3), [2/3, Infinity)", a.Complement()},
+		{"(-Infinity, 1/2]", b.Complement()},
+		{"[1, 2)", a.Scale(big.NewRat(3, 1))},
+		{"(-2, -1]", a.Scale(big.NewRat(-3, 1))},
+		{"(-Infinity, -1/2)", b.Scale(big.NewRat(-1, 1))},
+		{"[0, 0]", b.Scale(new(big.Rat))},
+		{"[0, 1/3)", a.Translate(big.NewRat(-1, 3))},
+		{"[1/3, 2/3)", a.Scale(big.NewRat(7, 1)).Translate(big.NewRat(1, 1)).Translate(big.NewRat(-1, 1)).Scale(big.NewRat(1, 7))},
+		{"(-Infinity, 0), (0, Infinity)", NewRat(new(big.Rat), false, new(big.Rat), false).Complement()},
+		{"(-Infinity, 0), (0, Infinity)", NewRat(new(big.Rat), false, new(big.Rat), false).Complement().Scale(big.NewRat(-1, 1))},
+	}
+
+	for _, c := range cases {
+		t.Run(c.result, func(t *testing.T) {
+			if err := c.s.Validate(); err != nil {
+				t.Fatal(err)
+			}
+			r := c.s.String()
+			if r != c.result {
+				t.Fatalf("Expected '%v', but got '%v'", c.result, r)
+			}
+		})
+	}
+
+	if !a.Contains(third) || a.Contains(twoThirds) || !a.Contains(big.NewRat(1, 2)) {
+		t.Fatalf("Unexpected membership in '%v'", a.String())
+	}
+}
+
+func TestRatSetToSet(t *testing.T) {
+	type testcase struct {
+		s      RatSet
+		result string
+	}
+
+	third := big.NewRat(1, 3)
+	cases := []testcase{
+		{NewRat(third, false, big.NewRat(2, 3), true), "[0.33333, 0.66667)"},
+		{NewRat(big.NewRat(1, 4), true, big.NewRat(1, 2), false), "(0.25, 0.5]"},
+		{NewRat(third, false, third, false), "[0.33333, 0.33334]"},
+		{NewRat(third, false, third, false).Complement(), "(-Infinity, Infinity)"},
+		{NewRat(big.NewRat(1, 4), false, big.NewRat(1, 4), false).Complement(), "(-Infinity, 0.25), (0.25, Infinity)"},
+		{NewRat(nil, false, big.NewRat(-1, 3), true), "(-Infinity, -0.33333)"},
+		{NewRat(big.NewRat(123456, 1), false, big.NewRat(200000, 1), false), "[123450, 200000]"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.result, func(t *testing.T) {
+			s, err := c.s.ToSet(5)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := s.Validate(); err != nil {
+				t.Fatal(err)
+			}
+			r := s.String()
+			if r != c.result {
+				t.Fatalf("Expected '%v', but got '%v'", c.result, r)
+			}
+
+			// Converting back gives a superset.
+			back := RatFromSet(s)
+			if !c.s.Difference(back).IsEmpty() {
+				t.Fatalf("Expected '%v' to contain '%v'", back.String(), c.s.String())
+			}
+		})
+	}
+}
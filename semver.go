@@ -0,0 +1,470 @@
+package apis
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Version is a semantic version, as described at https://semver.org.
+type Version struct {
+	Major, Minor, Patch uint64
+	// Prerelease holds the dot separated identifiers following '-'.
+	Prerelease []string
+	// Build holds the dot separated identifiers following '+'. Build metadata
+	// does not take part in ordering.
+	Build []string
+}
+
+// ParseVersion parses a full semantic version, with an optional leading 'v'.
+func ParseVersion(s string) (Version, error) {
+	p, err := parsePartial(s)
+	if err != nil {
+		return Version{}, err
+	}
+	if p.n < 3 {
+		return Version{}, fmt.Errorf("%q is not a complete version", s)
+	}
+	return p.v, nil
+}
+
+func (v Version) String() string {
+	b := strings.Builder{}
+	fmt.Fprintf(&b, "%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if len(v.Prerelease) > 0 {
+		b.WriteString("-" + strings.Join(v.Prerelease, "."))
+	}
+	if len(v.Build) > 0 {
+		b.WriteString("+" + strings.Join(v.Build, "."))
+	}
+	return b.String()
+}
+
+// Compare returns -1, 0 or 1 as v has lower, equal or higher precedence
+// than o.
+func (v Version) Compare(o Version) int {
+	if c := compareUint(v.Major, o.Major); c != 0 {
+		return c
+	}
+	if c := compareUint(v.Minor, o.Minor); c != 0 {
+		return c
+	}
+	if c := compareUint(v.Patch, o.Patch); c != 0 {
+		return c
+	}
+
+	// A prerelease has lower precedence than the associated normal version.
+	switch {
+	case len(v.Prerelease) == 0 && len(o.Prerelease) == 0:
+		return 0
+	case len(v.Prerelease) == 0:
+		return 1
+	case len(o.Prerelease) == 0:
+		return -1
+	}
+
+	for i := 0; i < len(v.Prerelease) && i < len(o.Prerelease); i++ {
+		if c := compareIdentifier(v.Prerelease[i], o.Prerelease[i]); c != 0 {
+			return c
+		}
+	}
+	return compareUint(uint64(len(v.Prerelease)), uint64(len(o.Prerelease)))
+}
+
+func compareVersions(a, b Version) int {
+	return a.Compare(b)
+}
+
+func compareUint(a, b uint64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// compareIdentifier orders prerelease identifiers: numeric identifiers
+// numerically, and below alphanumeric ones, which are ordered lexically.
+func compareIdentifier(a, b string) int {
+	an, aErr := strconv.ParseUint(a, 10, 64)
+	bn, bErr := strconv.ParseUint(b, 10, 64)
+	switch {
+	case aErr == nil && bErr == nil:
+		return compareUint(an, bn)
+	case aErr == nil:
+		return -1
+	case bErr == nil:
+		return 1
+	}
+	return strings.Compare(a, b)
+}
+
+// partial is a version whose trailing components may be omitted or
+// wildcards, as in "1.2", "1.2.x" or "*". Only the first n components are
+// specified.
+type partial struct {
+	v Version
+	n int
+}
+
+func parsePartial(s string) (partial, error) {
+	var p partial
+	rest := strings.TrimPrefix(s, "v")
+	if i := strings.IndexByte(rest, '+'); i >= 0 {
+		build := strings.Split(rest[i+1:], ".")
+		if err := checkIdentifiers(build); err != nil {
+			return p, fmt.Errorf("%q: %w", s, err)
+		}
+		p.v.Build = build
+		rest = rest[:i]
+	}
+	if i := strings.IndexByte(rest, '-'); i >= 0 {
+		pre := strings.Split(rest[i+1:], ".")
+		if err := checkIdentifiers(pre); err != nil {
+			return p, fmt.Errorf("%q: %w", s, err)
+		}
+		for _, id := range pre {
+			// Numeric prerelease identifiers, unlike build metadata, must not
+			// have leading zeros.
+			if len(id) > 1 && id[0] == '0' && strings.Trim(id, "0123456789") == "" {
+				return p, fmt.Errorf("%q has a prerelease identifier %q with a leading zero", s, id)
+			}
+		}
+		p.v.Prerelease = pre
+		rest = rest[:i]
+	}
+
+	parts := strings.Split(rest, ".")
+	if len(parts) > 3 {
+		return p, fmt.Errorf("%q has too many components", s)
+	}
+	fields := []*uint64{&p.v.Major, &p.v.Minor, &p.v.Patch}
+	wild := false
+	for i, c := range parts {
+		if c == "x" || c == "X" || c == "*" {
+			wild = true
+			continue
+		}
+		if wild {
+			return p, fmt.Errorf("%q has a component after a wildcard", s)
+		}
+		if c == "" || (len(c) > 1 && c[0] == '0') {
+			return p, fmt.Errorf("%q has an invalid component %q", s, c)
+		}
+		n, err := strconv.ParseUint(c, 10, 64)
+		if err != nil {
+			return p, fmt.Errorf("%q has an invalid component %q", s, c)
+		}
+		*fields[i] = n
+		p.n++
+	}
+	if p.n < 3 && (len(p.v.Prerelease) > 0 || len(p.v.Build) > 0) {
+		return p, fmt.Errorf("%q has a prerelease or build on a partial version", s)
+	}
+	return p, nil
+}
+
+func checkIdentifiers(ids []string) error {
+	for _, id := range ids {
+		if id == "" {
+			return fmt.Errorf("empty identifier")
+		}
+		for _, r := range id {
+			if !(r == '-' || r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z') {
+				return fmt.Errorf("invalid character %q in identifier %q", r, id)
+			}
+		}
+	}
+	return nil
+}
+
+// floor returns the lowest release version matching the partial version.
+func (p partial) floor() Version {
+	return Version{Major: p.v.Major, Minor: p.v.Minor, Patch: p.v.Patch, Prerelease: p.v.Prerelease}
+}
+
+// bump returns the lowest version, including prereleases, above every version
+// that matches the first n components of the partial version. It fails if
+// the nth component is already the largest possible.
+func (p partial) bump(n int) (Version, error) {
+	v := Version{Major: p.v.Major, Minor: p.v.Minor, Patch: p.v.Patch, Prerelease: []string{"0"}}
+	fields := []*uint64{&v.Major, &v.Minor, &v.Patch}
+	if *fields[n-1] == math.MaxUint64 {
+		return Version{}, fmt.Errorf("version component %d is too large to bound a range", *fields[n-1])
+	}
+	*fields[n-1]++
+	for _, f := range fields[n:] {
+		*f = 0
+	}
+	return v, nil
+}
+
+// upTo returns the versions from the partial version's floor up to, but not
+// including, bump(n).
+func (p partial) upTo(n int) (ranges[Version], error) {
+	u, err := p.bump(n)
+	if err != nil {
+		return ranges[Version]{}, err
+	}
+	return interval(p.floor(), false, u, true, compareVersions), nil
+}
+
+// matching returns every version matching the partial version.
+func (p partial) matching() (ranges[Version], error) {
+	if p.n == 0 {
+		return allVersions(), nil
+	}
+	if p.n == 3 {
+		return interval(p.v, false, p.v, false, compareVersions), nil
+	}
+	return p.upTo(p.n)
+}
+
+// minVersion is the lowest possible version.
+var minVersion = Version{Prerelease: []string{"0"}}
+
+// allVersions returns every version. Version sets are always bounded below by
+// minVersion rather than extending to the bottom of the domain, so that each
+// set has a single representation.
+func allVersions() ranges[Version] {
+	return atLeast(minVersion, false)
+}
+
+// versionsBelow returns every version up to u.
+func versionsBelow(u Version, open bool) ranges[Version] {
+	return interval(minVersion, false, u, open, compareVersions)
+}
+
+// VersionSet is a set of semantic versions, such as those satisfying a
+// version constraint.
+type VersionSet struct {
+	r ranges[Version]
+}
+
+// ParseConstraint parses npm or Go style version constraints, such as
+// ">=1.2.0 <2.0.0 || 2.3.x" or ">= 1.2, < 2".
+//
+// Comparators separated by whitespace or commas must all hold, and '||'
+// separates alternatives. Supported comparators are =, !=, <, <=, >, >=,
+// tilde (~ or ~>) and caret (^) ranges, hyphen ranges ("1.2 - 2.3.4") and
+// x-ranges ("1.2.x", "1.*", "*"). Exclusive upper bounds on releases, and
+// bounds derived from partial versions, use the '-0' prerelease, so that
+// "<2.0.0" and "<2" exclude 2.0.0-alpha and "1.x" does not match 2.0.0-beta.
+//
+// Unlike npm, prereleases are ordinary members of the set, ordered by
+// precedence like any other version: "^1.2.3" contains 1.2.4-alpha. npm only
+// matches a prerelease if a comparator in the same alternative has a
+// prerelease on the same major.minor.patch, which depends on how the
+// constraint was written rather than on the versions it holds, so cannot be
+// kept through the set operations. To follow npm, check a prerelease against
+// the constraints that name its major.minor.patch before calling Contains.
+func ParseConstraint(s string) (VersionSet, error) {
+	res := ranges[Version]{}
+	for _, alt := range strings.Split(s, "||") {
+		r, err := parseConjunction(alt)
+		if err != nil {
+			return VersionSet{}, err
+		}
+		res = res.union(r, compareVersions)
+	}
+	return VersionSet{res}, nil
+}
+
+func parseConjunction(s string) (ranges[Version], error) {
+	tokens := strings.Fields(strings.ReplaceAll(s, ",", " "))
+	res := allVersions()
+
+	for i := 0; i < len(tokens); i++ {
+		var r ranges[Version]
+		var err error
+		switch {
+		case i+2 < len(tokens) && tokens[i+1] == "-":
+			r, err = parseHyphen(tokens[i], tokens[i+2])
+			i += 2
+		case strings.TrimLeft(tokens[i], "<>=!~^") == "" && i+1 < len(tokens):
+			// An operator separated from its version by whitespace.
+			r, err = parseComparator(tokens[i] + tokens[i+1])
+			i++
+		default:
+			r, err = parseComparator(tokens[i])
+		}
+		if err != nil {
+			return r, err
+		}
+		res = res.intersection(r, compareVersions)
+	}
+	return res, nil
+}
+
+func parseHyphen(ls, us string) (ranges[Version], error) {
+	l, err := parsePartial(ls)
+	if err != nil {
+		return ranges[Version]{}, err
+	}
+	u, err := parsePartial(us)
+	if err != nil {
+		return ranges[Version]{}, err
+	}
+	lr := allVersions()
+	if l.n > 0 {
+		lr = atLeast(l.floor(), false)
+	}
+	ur := allVersions()
+	if u.n == 3 {
+		ur = versionsBelow(u.v, false)
+	} else if u.n > 0 {
+		b, err := u.bump(u.n)
+		if err != nil {
+			return ranges[Version]{}, err
+		}
+		ur = versionsBelow(b, true)
+	}
+	return lr.intersection(ur, compareVersions), nil
+}
+
+func parseComparator(s string) (ranges[Version], error) {
+	op := s[:len(s)-len(strings.TrimLeft(s, "<>=!~^"))]
+	p, err := parsePartial(s[len(op):])
+	if err != nil {
+		return ranges[Version]{}, err
+	}
+
+	all := allVersions()
+	switch op {
+	case "", "=":
+		return p.matching()
+	case "!=":
+		m, err := p.matching()
+		return allVersions().difference(m, compareVersions), err
+	case ">":
+		if p.n == 0 {
+			return ranges[Version]{}, nil
+		}
+		if p.n == 3 {
+			return atLeast(p.v, true), nil
+		}
+		b, err := p.bump(p.n)
+		return atLeast(b, false), err
+	case ">=":
+		if p.n == 0 {
+			return all, nil
+		}
+		return atLeast(p.floor(), false), nil
+	case "<":
+		if p.n == 0 {
+			return ranges[Version]{}, nil
+		}
+		if len(p.v.Prerelease) > 0 {
+			return versionsBelow(p.v, true), nil
+		}
+		// Like npm, "<2.0.0" excludes the prereleases of 2.0.0 as "<2" does.
+		return versionsBelow(Version{Major: p.v.Major, Minor: p.v.Minor, Patch: p.v.Patch, Prerelease: []string{"0"}}, true), nil
+	case "<=":
+		if p.n == 0 {
+			return all, nil
+		}
+		if p.n == 3 {
+			return versionsBelow(p.v, false), nil
+		}
+		b, err := p.bump(p.n)
+		return versionsBelow(b, true), err
+	case "~", "~>":
+		switch p.n {
+		case 0:
+			return all, nil
+		case 1:
+			return p.upTo(1)
+		}
+		return p.upTo(2)
+	case "^":
+		switch {
+		case p.n == 0:
+			return all, nil
+		case p.v.Major > 0 || p.n == 1:
+			return p.upTo(1)
+		case p.v.Minor > 0 || p.n == 2:
+			return p.upTo(2)
+		}
+		return p.upTo(3)
+	}
+	return ranges[Version]{}, fmt.Errorf("unknown operator %q in %q", op, s)
+}
+
+func (a VersionSet) Union(b VersionSet) VersionSet {
+	return VersionSet{a.r.union(b.r, compareVersions)}
+}
+
+func (a VersionSet) Intersection(b VersionSet) VersionSet {
+	return VersionSet{a.r.intersection(b.r, compareVersions)}
+}
+
+func (a VersionSet) Difference(b VersionSet) VersionSet {
+	return VersionSet{a.r.difference(b.r, compareVersions)}
+}
+
+func (a VersionSet) Complement() VersionSet {
+	return VersionSet{allVersions().difference(a.r, compareVersions)}
+}
+
+// Contains reports whether v is in the set. Prereleases are matched like any
+// other version; see ParseConstraint for how this differs from npm.
+func (a VersionSet) Contains(v Version) bool {
+	return a.r.contains(v, compareVersions)
+}
+
+// IsEmpty reports whether no version satisfies the set, for instance when
+// intersecting incompatible constraints.
+func (a VersionSet) IsEmpty() bool {
+	return a.r.isEmpty()
+}
+
+func (a VersionSet) Validate() error {
+	return a.r.validate(compareVersions)
+}
+
+// String renders the set as a constraint that ParseConstraint accepts.
+func (a VersionSet) String() string {
+	if a.r.isEmpty() {
+		return "<" + minVersion.String()
+	}
+
+	alts := []string{}
+	cur := []string{}
+	in := false
+	for i, v := range a.r.items {
+		switch {
+		case i == 0 && v.b == lower && !v.open && compareVersions(v.d, minVersion) == 0:
+			// Bounded only by the lowest version.
+			cur = []string{}
+		case v.b == lower && v.open:
+			cur = append(cur, ">"+v.d.String())
+		case v.b == lower:
+			cur = append(cur, ">="+v.d.String())
+		case v.b == upper && v.open && len(v.d.Prerelease) == 0:
+			// "<" would exclude the prereleases of a release.
+			alts = append(alts, strings.Join(append(cur, "<="+v.d.String(), "!="+v.d.String()), " "))
+			cur = nil
+		case v.b == upper && v.open:
+			alts = append(alts, strings.Join(append(cur, "<"+v.d.String()), " "))
+			cur = nil
+		case v.b == upper:
+			alts = append(alts, strings.Join(append(cur, "<="+v.d.String()), " "))
+			cur = nil
+		case in:
+			cur = append(cur, "!="+v.d.String())
+		default:
+			alts = append(alts, v.d.String())
+		}
+		_, in = v.step(in)
+	}
+	if in {
+		if len(cur) == 0 {
+			cur = append(cur, "*")
+		}
+		alts = append(alts, strings.Join(cur, " "))
+	}
+	return strings.Join(alts, " || ")
+}
@@ -0,0 +1,209 @@
+package apis
+
+import (
+	"testing"
+)
+
+func TestVersionCompare(t *testing.T) {
+	// In increasing order of precedence, from https://semver.org.
+	ordered := []string{
+		"0.0.0-0",
+		"1.0.0-alpha",
+		"1.0.0-alpha.1",
+		"1.0.0-alpha.beta",
+		"1.0.0-beta",
+		"1.0.0-beta.2",
+		"1.0.0-beta.11",
+		"1.0.0-rc.1",
+		"1.0.0",
+		"2.0.0",
+		"2.1.0",
+		"2.1.1",
+	}
+
+	for i := 1; i < len(ordered); i++ {
+		a, err := ParseVersion(ordered[i-1])
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := ParseVersion(ordered[i])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if a.Compare(b) != -1 || b.Compare(a) != 1 {
+			t.Fatalf("Expected %v < %v", a, b)
+		}
+	}
+
+	for _, s := range []string{"1.2.3-0", "1.2.3-0a.10", "1.2.3-rc.0-1", "1.2.3+001"} {
+		if _, err := ParseVersion(s); err != nil {
+			t.Fatalf("Expected '%v' to be accepted, but got %v", s, err)
+		}
+	}
+
+	a, _ := ParseVersion("v1.2.3+build.5")
+	b, _ := ParseVersion("1.2.3")
+	if a.Compare(b) != 0 {
+		t.Fatalf("Expected build metadata to be ignored")
+	}
+
+	for _, s := range []string{"1.2", "01.2.3", "1.2.3-", "1.2.3-a..b", "1.2.x", "1.2.3-01", "1.2.3-rc.00"} {
+		if _, err := ParseVersion(s); err == nil {
+			t.Fatalf("Expected '%v' to be rejected", s)
+		}
+	}
+}
+
+func TestParseConstraint(t *testing.T) {
+	type testcase struct {
+		constraint string
+		result     string
+	}
+
+	cases := []testcase{
+		{">=1.2.0 <2.0.0 || 2.3.x", ">=1.2.0 <2.0.0-0 || >=2.3.0 <2.4.0-0"},
+		{">= 1.2, < 2", ">=1.2.0 <2.0.0-0"},
+		{"1.2.3", "1.2.3"},
+		{"v1.2.3", "1.2.3"},
+		{"*", "*"},
+		{"", "*"},
+		{"1.x", ">=1.0.0 <2.0.0-0"},
+		{"~1.2.3", ">=1.2.3 <1.3.0-0"},
+		{"~1", ">=1.0.0 <2.0.0-0"},
+		{"^1.2.3", ">=1.2.3 <2.0.0-0"},
+		{"^0.2.3", ">=0.2.3 <0.3.0-0"},
+		{"^0.0.3", ">=0.0.3 <0.0.4-0"},
+		{"^0.0", ">=0.0.0 <0.1.0-0"},
+		{"1.2.3 - 2.3.4", ">=1.2.3 <=2.3.4"},
+		{"1.2 - 2.3", ">=1.2.0 <2.4.0-0"},
+		{">1.2", ">=1.3.0-0"},
+		{"<=1.2", "<1.3.0-0"},
+		{"<1.2", "<1.2.0-0"},
+		{">=1.0.0 !=1.5.0 <2.0.0", ">=1.0.0 !=1.5.0 <2.0.0-0"},
+		{"<1.0.0-rc.1", "<1.0.0-rc.1"},
+		// "<1.0.0" excludes the prereleases of 1.0.0, which ">=1.0.0" does too.
+		{"<1.0.0 || >=1.0.0", "<1.0.0-0 || >=1.0.0"},
+		{"<1.0.0 || >=1.0.0-0", "*"},
+		{"<1.0.0 || 1.0.0", "<1.0.0-0 || 1.0.0"},
+		{"<=1.0.0 !=1.0.0", "<=1.0.0 !=1.0.0"},
+		{">2.0.0 <1.0.0", "<0.0.0-0"},
+		{"1.0.0 || 3.0.0", "1.0.0 || 3.0.0"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.constraint, func(t *testing.T) {
+			s, err := ParseConstraint(c.constraint)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := s.Validate(); err != nil {
+				t.Fatal(err)
+			}
+			r := s.String()
+			if r != c.result {
+				t.Fatalf("Expected '%v', but got '%v'", c.result, r)
+			}
+
+			// The rendered constraint describes the same set.
+			p, err := ParseConstraint(r)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if p.String() != r {
+				t.Fatalf("Expected '%v' to round trip, but got '%v'", r, p.String())
+			}
+		})
+	}
+
+	for _, s := range []string{
+		">>1.2.3", "1.2.3.4", "1.x.3", "1.2-beta",
+		// The range would need a component one above the largest possible.
+		"1.18446744073709551615", "<=0.18446744073709551615", "^18446744073709551615.0.0", "~1.18446744073709551615.0", "1 - 2.18446744073709551615",
+	} {
+		if _, err := ParseConstraint(s); err == nil {
+			t.Fatalf("Expected '%v' to be rejected", s)
+		}
+	}
+}
+
+func TestVersionSetOperations(t *testing.T) {
+	parse := func(s string) VersionSet {
+		v, err := ParseConstraint(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return v
+	}
+	version := func(s string) Version {
+		v, err := ParseVersion(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return v
+	}
+
+	a := parse("^1.2.0")
+	b := parse(">=1.4.0 || 0.9.x")
+
+	if r := a.Intersection(b).String(); r != ">=1.4.0 <2.0.0-0" {
+		t.Fatalf("Unexpected intersection '%v'", r)
+	}
+	if r := a.Union(b).String(); r != ">=0.9.0 <0.10.0-0 || >=1.2.0" {
+		t.Fatalf("Unexpected union '%v'", r)
+	}
+	// The difference keeps the prereleases of 1.4.0, which ">=1.4.0" lacks.
+	if r := a.Difference(b).String(); r != ">=1.2.0 <=1.4.0 !=1.4.0" {
+		t.Fatalf("Unexpected difference '%v'", r)
+	}
+	if r := a.Complement().String(); r != "<=1.2.0 !=1.2.0 || >=2.0.0-0" {
+		t.Fatalf("Unexpected complement '%v'", r)
+	}
+	if !a.Intersection(parse("~2.1")).IsEmpty() {
+		t.Fatalf("Expected incompatible constraints to be empty")
+	}
+
+	empty := parse("<0.0.0-0")
+	if !empty.IsEmpty() || empty.Complement().String() != "*" || parse("*").Complement().String() != "<0.0.0-0" {
+		t.Fatalf("Unexpected empty set '%v'", empty.String())
+	}
+
+	type membership struct {
+		v  string
+		in bool
+	}
+	for _, m := range []membership{
+		{"1.1.9", false},
+		{"1.2.0-rc.1", false},
+		{"1.2.0", true},
+		{"1.9.9", true},
+		{"2.0.0-alpha", false},
+		{"2.0.0", false},
+		// Prereleases are members by precedence, unlike in npm.
+		{"1.2.4-alpha", true},
+	} {
+		if a.Contains(version(m.v)) != m.in {
+			t.Fatalf("Expected membership of %v in '%v' to be %v", m.v, a.String(), m.in)
+		}
+	}
+
+	x := parse(">=1.0.0 !=1.5.0 <2.0.0")
+	if x.Contains(version("1.5.0")) || !x.Contains(version("1.5.1")) || !x.Contains(version("1.0.0")) {
+		t.Fatalf("Unexpected membership in '%v'", x.String())
+	}
+}
+
+func TestConstraintPrereleases(t *testing.T) {
+	s, err := ParseConstraint(">=1.2.0 <2.0.0 || 2.3.x")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range []string{"2.0.0-0", "2.0.0-beta", "2.0.0", "2.3.0-rc.1"} {
+		version, err := ParseVersion(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s.Contains(version) {
+			t.Fatalf("Expected '%v' not to contain %v", s.String(), v)
+		}
+	}
+}
@@ -0,0 +1,253 @@
+package apis
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cockroachdb/apd/v3"
+)
+
+// StepFunction is a piecewise-constant function from decimals to decimals,
+// such as a rate schedule.
+//
+// Like Set, it is stored as a sorted list of breakpoints, each recording the
+// function's value at the breakpoint and just above it; below the first
+// breakpoint the function takes the value below. Every breakpoint changes the
+// value, so equal functions have equal breakpoints. The zero StepFunction is
+// zero everywhere.
+type StepFunction struct {
+	below  apd.Decimal
+	breaks []breakpoint
+}
+
+type breakpoint struct {
+	d     apd.Decimal
+	at    apd.Decimal
+	after apd.Decimal
+}
+
+// ConstantStep returns the function that is c everywhere.
+func ConstantStep(c apd.Decimal) StepFunction {
+	return StepFunction{below: c}
+}
+
+// NewStepFunction returns the function that is v on s and zero elsewhere.
+func NewStepFunction(s Set, v apd.Decimal) StepFunction {
+	f := StepFunction{}
+	in := false
+	for _, i := range s.items {
+		at, after := i.step(in)
+		in = after
+
+		// Infinities bound the domain rather than being part of it.
+		switch {
+		case i.d.Form != apd.Infinite:
+			f.breaks = appendBreakpoint(f.breaks, f.valueBefore(len(f.breaks)), i.d, indicator(at, v), indicator(after, v))
+		case i.d.Negative:
+			f.below = indicator(after, v)
+		}
+	}
+	return f
+}
+
+func indicator(in bool, v apd.Decimal) apd.Decimal {
+	if in {
+		return v
+	}
+	return apd.Decimal{}
+}
+
+// appendBreakpoint appends the breakpoint at d, unless the value does not
+// change there.
+func appendBreakpoint(breaks []breakpoint, before, d, at, after apd.Decimal) []breakpoint {
+	if before.Cmp(&at) == 0 && at.Cmp(&after) == 0 {
+		return breaks
+	}
+	return append(breaks, breakpoint{d, at, after})
+}
+
+// valueBefore returns the value just below breakpoint i.
+func (f StepFunction) valueBefore(i int) apd.Decimal {
+	if i == 0 {
+		return f.below
+	}
+	return f.breaks[i-1].after
+}
+
+// At returns the value of the function at d.
+func (f StepFunction) At(d apd.Decimal) apd.Decimal {
+	for i, b := range f.breaks {
+		switch b.d.Cmp(&d) {
+		case 0:
+			return b.at
+		case 1:
+			return f.valueBefore(i)
+		}
+	}
+	return f.valueBefore(len(f.breaks))
+}
+
+// combineSteps sweeps both functions' breakpoints in a single pass, as combine
+// does for ranges, evaluating op pointwise.
+func combineSteps(f, g StepFunction, op func(x, y apd.Decimal) apd.Decimal) StepFunction {
+	res := StepFunction{below: op(f.below, g.below)}
+	inF, inG := f.below, g.below
+	fi, gi := 0, 0
+	for fi < len(f.breaks) || gi < len(g.breaks) {
+		var d apd.Decimal
+		var c int
+		switch {
+		case fi >= len(f.breaks):
+			c = 1
+		case gi >= len(g.breaks):
+			c = -1
+		default:
+			c = f.breaks[fi].d.Cmp(&g.breaks[gi].d)
+		}
+
+		atF, afterF := inF, inF
+		atG, afterG := inG, inG
+		if c <= 0 {
+			d, atF, afterF = f.breaks[fi].d, f.breaks[fi].at, f.breaks[fi].after
+			fi++
+		}
+		if c >= 0 {
+			d, atG, afterG = g.breaks[gi].d, g.breaks[gi].at, g.breaks[gi].after
+			gi++
+		}
+
+		before := res.valueBefore(len(res.breaks))
+		res.breaks = appendBreakpoint(res.breaks, before, d, op(atF, atG), op(afterF, afterG))
+		inF, inG = afterF, afterG
+	}
+	return res
+}
+
+// Add returns the pointwise sum f + g.
+func (f StepFunction) Add(g StepFunction) StepFunction {
+	return combineSteps(f, g, func(x, y apd.Decimal) apd.Decimal {
+		var res apd.Decimal
+		_, _ = apd.BaseContext.Add(&res, &x, &y)
+		return positiveZero(res)
+	})
+}
+
+// Min returns the pointwise minimum of f and g.
+func (f StepFunction) Min(g StepFunction) StepFunction {
+	return combineSteps(f, g, func(x, y apd.Decimal) apd.Decimal {
+		if x.Cmp(&y) <= 0 {
+			return x
+		}
+		return y
+	})
+}
+
+// Max returns the pointwise maximum of f and g.
+func (f StepFunction) Max(g StepFunction) StepFunction {
+	return combineSteps(f, g, func(x, y apd.Decimal) apd.Decimal {
+		if x.Cmp(&y) >= 0 {
+			return x
+		}
+		return y
+	})
+}
+
+// Scale returns the function multiplied by the finite decimal k.
+func (f StepFunction) Scale(k apd.Decimal) StepFunction {
+	return combineSteps(f, StepFunction{}, func(x, _ apd.Decimal) apd.Decimal {
+		var res apd.Decimal
+		_, _ = apd.BaseContext.Mul(&res, &x, &k)
+		return positiveZero(res)
+	})
+}
+
+// positiveZero clears the sign of a zero, so that it prints as "0".
+func positiveZero(d apd.Decimal) apd.Decimal {
+	if d.IsZero() {
+		d.Negative = false
+	}
+	return d
+}
+
+// segment is a maximal interval on which the function is constant.
+type segment struct {
+	iv Interval
+	v  apd.Decimal
+}
+
+// segments decomposes the function into maximal constant intervals, in
+// increasing order, covering every decimal.
+func (f StepFunction) segments() []segment {
+	res := []segment{}
+	cur := segment{Interval{Lower: negativeInfinity, LowerOpen: true}, f.below}
+	for _, b := range f.breaks {
+		cur.iv.Upper, cur.iv.UpperOpen = b.d, true
+		res = appendSegment(res, cur)
+		res = appendSegment(res, segment{Interval{Lower: b.d, Upper: b.d}, b.at})
+		cur = segment{Interval{Lower: b.d, LowerOpen: true}, b.after}
+	}
+	cur.iv.Upper, cur.iv.UpperOpen = positiveInfinity, true
+	return appendSegment(res, cur)
+}
+
+// appendSegment adds s after the last segment, extending the last segment
+// instead if they have the same value.
+func appendSegment(segs []segment, s segment) []segment {
+	if s.iv.IsEmpty() {
+		return segs
+	}
+	if n := len(segs); n > 0 && segs[n-1].v.Cmp(&s.v) == 0 {
+		segs[n-1].iv.Upper, segs[n-1].iv.UpperOpen = s.iv.Upper, s.iv.UpperOpen
+		return segs
+	}
+	return append(segs, s)
+}
+
+// Integral returns the integral of the function over s. It is infinite if
+// the function is non-zero on an unbounded part of s, and an error if that
+// would be both positive and negative infinity.
+func (f StepFunction) Integral(s Set) (apd.Decimal, error) {
+	var total apd.Decimal
+	for _, seg := range f.segments() {
+		if seg.v.IsZero() {
+			continue
+		}
+		m := FromIntervals(seg.iv).Intersection(s).Measure()
+		if m.IsZero() {
+			continue
+		}
+		var area apd.Decimal
+		_, _ = apd.BaseContext.Mul(&area, &seg.v, &m)
+		if _, err := apd.BaseContext.Add(&total, &total, &area); err != nil {
+			return apd.Decimal{}, fmt.Errorf("integral over %v does not converge: %w", s.String(), err)
+		}
+	}
+	return total, nil
+}
+
+// Where returns the set of decimals at which the function's value satisfies
+// pred.
+func (f StepFunction) Where(pred func(v apd.Decimal) bool) Set {
+	ivs := []Interval{}
+	for _, seg := range f.segments() {
+		if pred(seg.v) {
+			ivs = append(ivs, seg.iv)
+		}
+	}
+	return FromIntervals(ivs...)
+}
+
+// AtLeast returns the level set {x | f(x) >= c}.
+func (f StepFunction) AtLeast(c apd.Decimal) Set {
+	return f.Where(func(v apd.Decimal) bool { return v.Cmp(&c) >= 0 })
+}
+
+// String renders each maximal constant interval with its value, like
+// "(-Infinity, 0): 0, [0, 10): 5, [10, Infinity): 0".
+func (f StepFunction) String() string {
+	parts := []string{}
+	for _, seg := range f.segments() {
+		parts = append(parts, fmt.Sprintf("%v: %v", formatInterval(seg.iv), &seg.v))
+	}
+	return strings.Join(parts, ", ")
+}
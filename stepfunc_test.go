@@ -0,0 +1,114 @@
+package apis
+
+import (
+	"testing"
+)
+
+func newStep(b1 boundDef, b2 boundDef, v string) StepFunction {
+	return NewStepFunction(newFromBounds(b1, b2), decimal(v))
+}
+
+func TestStepFunctionOperations(t *testing.T) {
+	a := newStep(boundDef{"0", false}, boundDef{"10", true}, "5")
+	b := newStep(boundDef{"5", false}, boundDef{"15", false}, "3")
+
+	type testcase struct {
+		f      StepFunction
+		result string
+	}
+
+	cases := []testcase{
+		{StepFunction{}, "(-Infinity, Infinity): 0"},
+		{a, "(-Infinity, 0): 0, [0, 10): 5, [10, Infinity): 0"},
+		{a.Add(b), "(-Infinity, 0): 0, [0, 5): 5, [5, 10): 8, [10, 15]: 3, (15, Infinity): 0"},
+		{a.Max(b), "(-Infinity, 0): 0, [0, 10): 5, [10, 15]: 3, (15, Infinity): 0"},
+		{a.Min(b), "(-Infinity, 5): 0, [5, 10): 3, [10, Infinity): 0"},
+		{a.Scale(decimal("-2")), "(-Infinity, 0): 0, [0, 10): -10, [10, Infinity): 0"},
+		{a.Scale(decimal("0")), "(-Infinity, Infinity): 0"},
+		{a.Add(a.Scale(decimal("-1"))), "(-Infinity, Infinity): 0"},
+		{newStep(boundDef{"0", false}, boundDef{"0", false}, "1"), "(-Infinity, 0): 0, [0, 0]: 1, (0, Infinity): 0"},
+		{newStep(boundDef{"-infinity", true}, boundDef{"infinity", true}, "2"), "(-Infinity, Infinity): 2"},
+		{ConstantStep(decimal("1")).Add(newStep(boundDef{"0", true}, boundDef{"infinity", true}, "1")), "(-Infinity, 0]: 1, (0, Infinity): 2"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.result, func(t *testing.T) {
+			r := c.f.String()
+			if r != c.result {
+				t.Fatalf("Expected '%v', but got '%v'", c.result, r)
+			}
+		})
+	}
+
+	if v := a.Add(b).At(decimal("10")); v.String() != "3" {
+		t.Fatalf("Expected value 3 at 10, but got %v", v.String())
+	}
+}
+
+func TestStepFunctionIntegral(t *testing.T) {
+	f := newStep(boundDef{"0", false}, boundDef{"10", true}, "5").Add(newStep(boundDef{"5", false}, boundDef{"15", false}, "3"))
+
+	type testcase struct {
+		f        StepFunction
+		s        Set
+		integral string
+	}
+
+	cases := []testcase{
+		{f, newFromBounds(boundDef{"-infinity", true}, boundDef{"infinity", true}), "80"},
+		{f, newFromBounds(boundDef{"2", false}, boundDef{"6", false}), "23"},
+		{f, newFromBounds(boundDef{"7", false}, boundDef{"7", false}), "0"},
+		{f, Set{}, "0"},
+		{ConstantStep(decimal("1")), newFromBounds(boundDef{"0", false}, boundDef{"infinity", true}), "Infinity"},
+	}
+
+	for _, c := range cases {
+		v, err := c.f.Integral(c.s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v.String() != c.integral {
+			t.Fatalf("Expected integral of '%v' over '%v' to be %v, but got %v", c.f, c.s.String(), c.integral, v.String())
+		}
+	}
+
+	diverging := ConstantStep(decimal("1")).Add(newStep(boundDef{"-infinity", true}, boundDef{"0", true}, "-2"))
+	if _, err := diverging.Integral(newFromBounds(boundDef{"-infinity", true}, boundDef{"infinity", true})); err == nil {
+		t.Fatalf("Expected integral of '%v' to be an error", diverging)
+	}
+}
+
+func TestStepFunctionAtLeast(t *testing.T) {
+	f := newStep(boundDef{"0", false}, boundDef{"10", true}, "5").Add(newStep(boundDef{"5", false}, boundDef{"15", false}, "3"))
+
+	type testcase struct {
+		c      string
+		result string
+	}
+
+	cases := []testcase{
+		{"8", "[5, 10)"},
+		{"4", "[0, 10)"},
+		{"3", "[0, 15]"},
+		{"0", "(-Infinity, Infinity)"},
+		{"9", ""},
+	}
+
+	for _, c := range cases {
+		t.Run(c.result, func(t *testing.T) {
+			s := f.AtLeast(decimal(c.c))
+			if err := s.Validate(); err != nil {
+				t.Fatal(err)
+			}
+			if r := s.String(); r != c.result {
+				t.Fatalf("Expected '%v', but got '%v'", c.result, r)
+			}
+		})
+	}
+
+	dip := ConstantStep(decimal("1")).Add(newStep(boundDef{"2", false}, boundDef{"2", false}, "-1"))
+	s := dip.AtLeast(decimal("1"))
+	if r := s.String(); r != "(-Infinity, 2), (2, Infinity)" {
+		t.Fatalf("Expected '%v', but got '%v'", "(-Infinity, 2), (2, Infinity)", r)
+	}
+}
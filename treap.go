@@ -0,0 +1,203 @@
+package apis
+
+import (
+	"math/rand"
+
+	"github.com/cockroachdb/apd/v3"
+)
+
+// treapNode holds one maximal interval of a set. Nodes are ordered by
+// interval, and no node has a higher priority than its parent, which keeps
+// the tree balanced with high probability.
+//
+// Nodes are never modified once they are in a tree. Updates copy the O(log n)
+// nodes on the paths they change and share every other subtree, so old roots
+// remain valid versions of the set, and may be read concurrently.
+type treapNode struct {
+	iv          Interval
+	priority    uint64
+	size        int
+	left, right *treapNode
+}
+
+func newTreapNode(iv Interval) *treapNode {
+	iv.Lower, iv.Upper = cloneDecimal(iv.Lower), cloneDecimal(iv.Upper)
+	return &treapNode{iv: iv, priority: rand.Uint64(), size: 1}
+}
+
+func (t *treapNode) count() int {
+	if t == nil {
+		return 0
+	}
+	return t.size
+}
+
+// with returns a copy of t with the given children.
+func (t *treapNode) with(left, right *treapNode) *treapNode {
+	c := *t
+	c.left, c.right = left, right
+	c.size = left.count() + right.count() + 1
+	return &c
+}
+
+// below reports whether a lies entirely below b. If apart, they must also not
+// touch, as [0, 1) and [1, 2] do, so that they could not be merged.
+func below(a, b Interval, apart bool) bool {
+	c := a.Upper.Cmp(&b.Lower)
+	if c != 0 {
+		return c < 0
+	}
+	if apart {
+		return a.UpperOpen && b.LowerOpen
+	}
+	return a.UpperOpen || b.LowerOpen
+}
+
+// split separates t into the nodes for which inLeft is true, which must be a
+// prefix of the nodes in order, and the rest.
+func split(t *treapNode, inLeft func(Interval) bool) (*treapNode, *treapNode) {
+	if t == nil {
+		return nil, nil
+	}
+	if inLeft(t.iv) {
+		right, r := split(t.right, inLeft)
+		return t.with(t.left, right), r
+	}
+	l, left := split(t.left, inLeft)
+	return l, t.with(left, t.right)
+}
+
+// merge joins two treaps, where every node of l is below every node of r.
+func merge(l, r *treapNode) *treapNode {
+	switch {
+	case l == nil:
+		return r
+	case r == nil:
+		return l
+	case l.priority > r.priority:
+		return l.with(l.left, merge(l.right, r))
+	default:
+		return r.with(merge(l, r.left), r.right)
+	}
+}
+
+func leftmost(t *treapNode) *treapNode {
+	for t.left != nil {
+		t = t.left
+	}
+	return t
+}
+
+func rightmost(t *treapNode) *treapNode {
+	for t.right != nil {
+		t = t.right
+	}
+	return t
+}
+
+// insert returns t with every number in iv added.
+func insert(t *treapNode, iv Interval) *treapNode {
+	if iv.IsEmpty() {
+		return t
+	}
+	iv = iv.normalize()
+	l, rest := split(t, func(n Interval) bool { return below(n, iv, true) })
+	mid, r := split(rest, func(n Interval) bool { return !below(iv, n, true) })
+
+	// Absorb the intervals that overlap or touch iv.
+	if mid != nil {
+		first, last := leftmost(mid).iv, rightmost(mid).iv
+		if c := first.Lower.Cmp(&iv.Lower); c < 0 || c == 0 && !first.LowerOpen {
+			iv.Lower, iv.LowerOpen = first.Lower, first.LowerOpen
+		}
+		if c := last.Upper.Cmp(&iv.Upper); c > 0 || c == 0 && !last.UpperOpen {
+			iv.Upper, iv.UpperOpen = last.Upper, last.UpperOpen
+		}
+	}
+	return merge(merge(l, newTreapNode(iv)), r)
+}
+
+// remove returns t with every number in iv removed.
+func remove(t *treapNode, iv Interval) *treapNode {
+	if iv.IsEmpty() {
+		return t
+	}
+	iv = iv.normalize()
+	l, rest := split(t, func(n Interval) bool { return below(n, iv, false) })
+	mid, r := split(rest, func(n Interval) bool { return !below(iv, n, false) })
+	if mid == nil {
+		return merge(l, r)
+	}
+
+	// iv covers every interval it overlaps, apart from perhaps the ends of
+	// the first and last.
+	for _, piece := range FromIntervals(leftmost(mid).iv, rightmost(mid).iv).Difference(FromIntervals(iv)).Intervals() {
+		l = merge(l, newTreapNode(piece))
+	}
+	return merge(l, r)
+}
+
+// treapContains reports whether d is in any interval of t.
+func treapContains(t *treapNode, d apd.Decimal) bool {
+	point := Interval{Lower: d, Upper: d}
+	for t != nil {
+		switch {
+		case below(point, t.iv, false):
+			t = t.left
+		case below(t.iv, point, false):
+			t = t.right
+		default:
+			return true
+		}
+	}
+	return false
+}
+
+// treapFromIntervals builds a treap from sorted maximal intervals, as
+// Set.Intervals returns, in O(n).
+func treapFromIntervals(ivs []Interval) *treapNode {
+	// Build the tree along its right spine, which is kept on a stack.
+	spine := []*treapNode{}
+	for _, iv := range ivs {
+		n := newTreapNode(iv)
+		var last *treapNode
+		for len(spine) > 0 && spine[len(spine)-1].priority < n.priority {
+			last = spine[len(spine)-1]
+			spine = spine[:len(spine)-1]
+		}
+		n.left = last
+		if len(spine) > 0 {
+			spine[len(spine)-1].right = n
+		}
+		spine = append(spine, n)
+	}
+	if len(spine) == 0 {
+		return nil
+	}
+	root := spine[0]
+	fixSizes(root)
+	return root
+}
+
+func fixSizes(t *treapNode) int {
+	if t == nil {
+		return 0
+	}
+	t.size = fixSizes(t.left) + fixSizes(t.right) + 1
+	return t.size
+}
+
+// treapIntervals returns the intervals of t in order.
+func treapIntervals(t *treapNode) []Interval {
+	ivs := make([]Interval, 0, t.count())
+	var walk func(t *treapNode)
+	walk = func(t *treapNode) {
+		if t != nil {
+			walk(t.left)
+			ivs = append(ivs, t.iv)
+			walk(t.right)
+		}
+	}
+	walk(t)
+	return ivs
+}
@@ -0,0 +1,84 @@
+package apis
+
+import (
+	"fmt"
+
+	"github.com/cockroachdb/apd/v3"
+)
+
+// Universe is a bounded domain, such as percentages in [0, 100] or
+// probabilities in [0, 1], for sets whose complement should stay within it.
+type Universe struct {
+	u Set
+}
+
+func NewUniverse(u Set) Universe {
+	return Universe{u.Clone()}
+}
+
+// BoundedSet is a Set clamped to a Universe.
+type BoundedSet struct {
+	universe Set
+	s        Set
+}
+
+// New returns the numbers between l and h, clamped to the universe.
+func (u Universe) New(l apd.Decimal, lOpen bool, h apd.Decimal, hOpen bool) BoundedSet {
+	return u.Clamp(New(l, lOpen, h, hOpen))
+}
+
+// Clamp returns the members of s within the universe.
+func (u Universe) Clamp(s Set) BoundedSet {
+	return BoundedSet{u.u, s.Intersection(u.u)}
+}
+
+// All returns the whole universe.
+func (u Universe) All() BoundedSet {
+	return BoundedSet{u.u, u.u}
+}
+
+// Set returns the members of a.
+func (a BoundedSet) Set() Set {
+	return a.s.Clone()
+}
+
+// Universe returns the universe a is bounded by.
+func (a BoundedSet) Universe() Universe {
+	return Universe{a.universe}
+}
+
+func (a BoundedSet) check(b BoundedSet) {
+	if !a.universe.Equal(b.universe) {
+		panic(fmt.Sprintf("bounded sets have different universes %v and %v", a.universe.String(), b.universe.String()))
+	}
+}
+
+// Union panics if a and b have different universes, as does Intersection.
+func (a BoundedSet) Union(b BoundedSet) BoundedSet {
+	a.check(b)
+	return BoundedSet{a.universe, a.s.Union(b.s)}
+}
+
+func (a BoundedSet) Intersection(b BoundedSet) BoundedSet {
+	a.check(b)
+	return BoundedSet{a.universe, a.s.Intersection(b.s)}
+}
+
+// Complement returns the rest of the universe.
+func (a BoundedSet) Complement() BoundedSet {
+	return BoundedSet{a.universe, a.s.Complement().Intersection(a.universe)}
+}
+
+func (a BoundedSet) Contains(d apd.Decimal) bool {
+	return a.s.Contains(d)
+}
+
+func (a BoundedSet) IsEmpty() bool {
+	return a.s.IsEmpty()
+}
+
+// String renders only the members, which all lie within the universe, so
+// complements have no infinite tails.
+func (a BoundedSet) String() string {
+	return a.s.String()
+}
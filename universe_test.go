@@ -0,0 +1,55 @@
+package apis
+
+import (
+	"testing"
+)
+
+func TestBoundedSet(t *testing.T) {
+	percent := NewUniverse(newFromBounds(boundDef{"0", false}, boundDef{"100", false}))
+	probability := NewUniverse(newFromBounds(boundDef{"0", false}, boundDef{"1", false}))
+
+	a := percent.New(decimal("20"), false, decimal("30"), true)
+	b := percent.Clamp(newFromBounds(boundDef{"90", true}, boundDef{"infinity", true}))
+
+	type testcase struct {
+		result string
+		s      BoundedSet
+	}
+
+	cases := []testcase{
+		{"[20, 30)", a},
+		{"(90, 100]", b},
+		{"[0, 20), [30, 100]", a.Complement()},
+		{"[0, 90]", b.Complement()},
+		{"[20, 30), (90, 100]", a.Union(b)},
+		{"[0, 20), [30, 90]", a.Union(b).Complement()},
+		{"", percent.All().Complement()},
+		{"[0, 100]", percent.All().Complement().Complement()},
+		{"[0, 0.5)", probability.New(decimal("-infinity"), true, decimal("0.5"), true)},
+		{"", percent.New(decimal("200"), false, decimal("300"), false)},
+	}
+
+	for _, c := range cases {
+		t.Run(c.result, func(t *testing.T) {
+			s := c.s.Set()
+			if err := s.Validate(); err != nil {
+				t.Fatal(err)
+			}
+			r := c.s.String()
+			if r != c.result {
+				t.Fatalf("Expected '%v', but got '%v'", c.result, r)
+			}
+		})
+	}
+
+	if !a.Complement().Contains(decimal("0")) || a.Complement().Contains(decimal("-1")) {
+		t.Fatalf("Expected complement to be bounded by the universe")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("Expected combining sets in different universes to panic")
+		}
+	}()
+	a.Union(probability.All())
+}